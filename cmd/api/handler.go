@@ -47,6 +47,31 @@ func (app *Config) taskResult(w http.ResponseWriter, r *http.Request) {
 	_ = app.writeJSON(w, http.StatusOK, respPayload)
 }
 
+// connectionStatus returns the state of the cluster connections of an identifier.
+func (app *Config) connectionStatus(w http.ResponseWriter, r *http.Request) {
+	reqPayload := &util_sirius.ConnectionStatus{}
+	if err := app.readJSON(w, r, reqPayload); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(reqPayload.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(reqPayload, "connection status")
+	states, err := app.serverRequests.ConnectionStates(reqPayload.IdentifierToken, reqPayload.Refresh)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusBadRequest)
+		return
+	}
+	respPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully retrieved connection status",
+		Data:    states,
+	}
+	_ = app.writeJSON(w, http.StatusOK, respPayload)
+}
+
 // insertTask is used to bulk loading documents into buckets
 func (app *Config) insertTask(w http.ResponseWriter, r *http.Request) {
 	task := &bulk_loading_cb.InsertTask{}
@@ -1132,3 +1157,968 @@ func (app *Config) WarmUpBucket(w http.ResponseWriter, r *http.Request) {
 	}
 	_ = app.writeJSON(w, http.StatusOK, resPayload)
 }
+
+// incrementTask is used to increment counters in bulk.
+func (app *Config) incrementTask(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_loading_cb.IncrementTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.IncrementOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.IncrementOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// decrementTask is used to decrement counters in bulk.
+func (app *Config) decrementTask(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_loading_cb.DecrementTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.DecrementOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.DecrementOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// appendTask is used to append values to documents in bulk.
+func (app *Config) appendTask(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_loading_cb.AppendTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.AppendOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.AppendOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// prependTask is used to prepend values to documents in bulk.
+func (app *Config) prependTask(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_loading_cb.PrependTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.PrependOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.PrependOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// singleIncrementTask is used to increment counters of keys in a collection
+func (app *Config) singleIncrementTask(w http.ResponseWriter, r *http.Request) {
+	task := &key_based_loading_cb.SingleIncrementTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.SingleIncrementOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.SingleIncrementOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// singleDecrementTask is used to decrement counters of keys in a collection
+func (app *Config) singleDecrementTask(w http.ResponseWriter, r *http.Request) {
+	task := &key_based_loading_cb.SingleDecrementTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.SingleDecrementOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.SingleDecrementOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// singleAppendTask is used to append values to keys in a collection
+func (app *Config) singleAppendTask(w http.ResponseWriter, r *http.Request) {
+	task := &key_based_loading_cb.SingleAppendTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.SingleAppendOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.SingleAppendOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// singlePrependTask is used to prepend values to keys in a collection
+func (app *Config) singlePrependTask(w http.ResponseWriter, r *http.Request) {
+	task := &key_based_loading_cb.SinglePrependTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.SinglePrependOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.SinglePrependOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// lockTask is used to lock, contend on and unlock documents in a collection
+func (app *Config) lockTask(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_loading_cb.LockTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.LockOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.LockOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// singleLockTask is used to lock, contend on and unlock keys in a collection
+func (app *Config) singleLockTask(w http.ResponseWriter, r *http.Request) {
+	task := &key_based_loading_cb.SingleLockTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.SingleLockOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.SingleLockOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// singleUnlockTask is used to unlock keys in a collection with a known cas
+func (app *Config) singleUnlockTask(w http.ResponseWriter, r *http.Request) {
+	task := &key_based_loading_cb.SingleUnlockTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.SingleUnlockOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.SingleUnlockOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// scanTask is used to perform range or sampling scan on a collection
+func (app *Config) scanTask(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_loading_cb.ScanTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.ScanOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.ScanOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested scan",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// transactionTask is used to run multi document transactions on documents in a collection
+func (app *Config) transactionTask(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_loading_cb.TransactionTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.TransactionOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.TransactionOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested transactions",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// runSearchQueryTask runs full text search queries over a search index built on the template fields.
+func (app *Config) runSearchQueryTask(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_query_cb.SearchTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.SearchOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.SearchOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested search query running",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// runAnalyticsQueryTask runs analytics queries over analytics collections created on collections loaded by Sirius.
+func (app *Config) runAnalyticsQueryTask(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_query_cb.AnalyticsTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.AnalyticsOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.AnalyticsOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested analytics query running",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// runIndexTask runs an index operation on a collection.
+func (app *Config) runIndexTask(w http.ResponseWriter, r *http.Request, operation string) {
+	task := &util_cb.IndexTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	task.Operation = operation
+	log.Print(task, operation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, operation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested index operation",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// createIndexTask creates the indexes of a collection.
+func (app *Config) createIndexTask(w http.ResponseWriter, r *http.Request) {
+	app.runIndexTask(w, r, tasks.CreateIndexOperation)
+}
+
+// buildIndexTask builds the deferred indexes of a collection.
+func (app *Config) buildIndexTask(w http.ResponseWriter, r *http.Request) {
+	app.runIndexTask(w, r, tasks.BuildIndexOperation)
+}
+
+// watchIndexTask waits for the indexes of a collection to come online.
+func (app *Config) watchIndexTask(w http.ResponseWriter, r *http.Request) {
+	app.runIndexTask(w, r, tasks.WatchIndexOperation)
+}
+
+// dropIndexTask drops the indexes of a collection.
+func (app *Config) dropIndexTask(w http.ResponseWriter, r *http.Request) {
+	app.runIndexTask(w, r, tasks.DropIndexOperation)
+}
+
+// listIndexTask lists the indexes of a collection.
+func (app *Config) listIndexTask(w http.ResponseWriter, r *http.Request) {
+	app.runIndexTask(w, r, tasks.ListIndexOperation)
+}
+
+// runBucketTask runs a bucket management operation.
+func (app *Config) runBucketTask(w http.ResponseWriter, r *http.Request, operation string) {
+	task := &util_cb.BucketTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	task.Operation = operation
+	log.Print(task, operation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, operation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested bucket operation",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// createBucketTask creates a bucket.
+func (app *Config) createBucketTask(w http.ResponseWriter, r *http.Request) {
+	app.runBucketTask(w, r, tasks.CreateBucketOperation)
+}
+
+// dropBucketTask drops a bucket.
+func (app *Config) dropBucketTask(w http.ResponseWriter, r *http.Request) {
+	app.runBucketTask(w, r, tasks.DropBucketOperation)
+}
+
+// flushBucketTask flushes a bucket.
+func (app *Config) flushBucketTask(w http.ResponseWriter, r *http.Request) {
+	app.runBucketTask(w, r, tasks.FlushBucketOperation)
+}
+
+// runCollectionTask runs a scope or collection management operation.
+func (app *Config) runCollectionTask(w http.ResponseWriter, r *http.Request, operation string) {
+	task := &util_cb.CollectionTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	task.Operation = operation
+	log.Print(task, operation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, operation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested collection operation",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// createScopeTask creates scopes of a bucket in bulk.
+func (app *Config) createScopeTask(w http.ResponseWriter, r *http.Request) {
+	app.runCollectionTask(w, r, tasks.CreateScopeOperation)
+}
+
+// dropScopeTask drops scopes of a bucket in bulk.
+func (app *Config) dropScopeTask(w http.ResponseWriter, r *http.Request) {
+	app.runCollectionTask(w, r, tasks.DropScopeOperation)
+}
+
+// createCollectionTask creates collections of a bucket in bulk.
+func (app *Config) createCollectionTask(w http.ResponseWriter, r *http.Request) {
+	app.runCollectionTask(w, r, tasks.CreateCollectionOperation)
+}
+
+// dropCollectionTask drops collections of a bucket in bulk.
+func (app *Config) dropCollectionTask(w http.ResponseWriter, r *http.Request) {
+	app.runCollectionTask(w, r, tasks.DropCollectionOperation)
+}
+
+// fanOutBulkTask runs a bulk operation on many collections of a bucket in a single request.
+func (app *Config) fanOutBulkTask(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_loading_cb.FanOutTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.FanOutOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.FanOutOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested doc loading",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// runPlanTask runs a workload plan of many steps as a single request.
+func (app *Config) runPlanTask(w http.ResponseWriter, r *http.Request) {
+	task := &util_sirius.PlanTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.PlanOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.PlanOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested plan",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// scheduleTask runs a task at a time, after a delay or repeatedly on an interval or cron expression.
+func (app *Config) scheduleTask(w http.ResponseWriter, r *http.Request) {
+	task := &util_sirius.ScheduleTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.ScheduleOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.ScheduleOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully scheduled requested task",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
+
+// verifyDurability verifies the replica copies of documents against their active copy.
+func (app *Config) verifyDurability(w http.ResponseWriter, r *http.Request) {
+	task := &bulk_loading_cb.VerifyDurabilityTask{}
+	if err := app.readJSON(w, r, task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := checkIdentifierToken(task.IdentifierToken); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	log.Print(task, tasks.VerifyDurabilityOperation)
+	err := app.serverRequests.AddTask(task.IdentifierToken, tasks.VerifyDurabilityOperation, task)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	req, err := app.serverRequests.GetRequestOfIdentifier(task.IdentifierToken)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	resultSeed, err := task.Config(req, false)
+	if err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+		return
+	}
+	if err := app.taskManager.AddTask(task); err != nil {
+		_ = app.errorJSON(w, err, http.StatusUnprocessableEntity)
+	}
+	respPayload := util_sirius.TaskResponse{
+		Seed: fmt.Sprintf("%d", resultSeed),
+	}
+	resPayload := jsonResponse{
+		Error:   false,
+		Message: "Successfully started requested durability verification",
+		Data:    respPayload,
+	}
+	_ = app.writeJSON(w, http.StatusOK, resPayload)
+}
@@ -139,6 +139,28 @@ func registerInterfaces() {
 	gob.Register(&key_based_loading_cb.SingleSubDocRead{})
 	gob.Register(&key_based_loading_cb.SingleValidate{})
 	gob.Register(&util_cb.BucketWarmUpTask{})
+	gob.Register(&bulk_loading_cb.IncrementTask{})
+	gob.Register(&bulk_loading_cb.DecrementTask{})
+	gob.Register(&bulk_loading_cb.AppendTask{})
+	gob.Register(&bulk_loading_cb.PrependTask{})
+	gob.Register(&key_based_loading_cb.SingleIncrementTask{})
+	gob.Register(&key_based_loading_cb.SingleDecrementTask{})
+	gob.Register(&key_based_loading_cb.SingleAppendTask{})
+	gob.Register(&key_based_loading_cb.SinglePrependTask{})
+	gob.Register(&bulk_loading_cb.LockTask{})
+	gob.Register(&key_based_loading_cb.SingleLockTask{})
+	gob.Register(&key_based_loading_cb.SingleUnlockTask{})
+	gob.Register(&bulk_loading_cb.ScanTask{})
+	gob.Register(&bulk_loading_cb.TransactionTask{})
+	gob.Register(&bulk_query_cb.SearchTask{})
+	gob.Register(&bulk_query_cb.AnalyticsTask{})
+	gob.Register(&util_cb.IndexTask{})
+	gob.Register(&util_cb.BucketTask{})
+	gob.Register(&util_cb.CollectionTask{})
+	gob.Register(&bulk_loading_cb.FanOutTask{})
+	gob.Register(&util_sirius.PlanTask{})
+	gob.Register(&util_sirius.ScheduleTask{})
+	gob.Register(&bulk_loading_cb.VerifyDurabilityTask{})
 
 	r := sirius_documentation.Register{}
 	for _, i := range r.HelperStruct() {
@@ -31,6 +31,7 @@ func (app *Config) routes() http.Handler {
 
 	mux.Get("/check-online", app.testServer)
 	mux.Post("/result", app.taskResult)
+	mux.Post("/connection-status", app.connectionStatus)
 	mux.Post("/bulk-create", app.insertTask)
 	mux.Post("/bulk-delete", app.deleteTask)
 	mux.Post("/bulk-upsert", app.upsertTask)
@@ -58,6 +59,37 @@ func (app *Config) routes() http.Handler {
 	mux.Post("/single-sub-doc-read", app.SingleSubDocRead)
 	mux.Post("/single-doc-validate", app.SingleDocValidate)
 	mux.Post("/warmup-bucket", app.WarmUpBucket)
+	mux.Post("/bulk-increment", app.incrementTask)
+	mux.Post("/bulk-decrement", app.decrementTask)
+	mux.Post("/bulk-append", app.appendTask)
+	mux.Post("/bulk-prepend", app.prependTask)
+	mux.Post("/single-increment", app.singleIncrementTask)
+	mux.Post("/single-decrement", app.singleDecrementTask)
+	mux.Post("/single-append", app.singleAppendTask)
+	mux.Post("/single-prepend", app.singlePrependTask)
+	mux.Post("/bulk-lock", app.lockTask)
+	mux.Post("/single-lock", app.singleLockTask)
+	mux.Post("/single-unlock", app.singleUnlockTask)
+	mux.Post("/bulk-scan", app.scanTask)
+	mux.Post("/bulk-transaction", app.transactionTask)
+	mux.Post("/run-search-query", app.runSearchQueryTask)
+	mux.Post("/run-analytics-query", app.runAnalyticsQueryTask)
+	mux.Post("/create-index", app.createIndexTask)
+	mux.Post("/build-index", app.buildIndexTask)
+	mux.Post("/watch-index", app.watchIndexTask)
+	mux.Post("/drop-index", app.dropIndexTask)
+	mux.Post("/list-index", app.listIndexTask)
+	mux.Post("/create-bucket", app.createBucketTask)
+	mux.Post("/drop-bucket", app.dropBucketTask)
+	mux.Post("/flush-bucket", app.flushBucketTask)
+	mux.Post("/create-scope", app.createScopeTask)
+	mux.Post("/drop-scope", app.dropScopeTask)
+	mux.Post("/create-collection", app.createCollectionTask)
+	mux.Post("/drop-collection", app.dropCollectionTask)
+	mux.Post("/bulk-fan-out", app.fanOutBulkTask)
+	mux.Post("/run-plan", app.runPlanTask)
+	mux.Post("/schedule-task", app.scheduleTask)
+	mux.Post("/verify-durability", app.verifyDurability)
 
 	return mux
 }
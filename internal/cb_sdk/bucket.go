@@ -1,10 +1,15 @@
 package cb_sdk
 
-import "github.com/couchbase/gocb/v2"
+import (
+	"github.com/couchbase/gocb/v2"
+	"sync"
+)
 
 type BucketObject struct {
 	bucket *gocb.Bucket            `json:"-"`
 	scopes map[string]*ScopeObject `json:"-"`
+	Health ConnectionHealth        `json:"-"`
+	lock   sync.Mutex              `json:"-"`
 }
 
 func (b *BucketObject) setScopeObject(scopeName string, s *ScopeObject) {
@@ -12,6 +17,8 @@ func (b *BucketObject) setScopeObject(scopeName string, s *ScopeObject) {
 }
 
 func (b *BucketObject) getScopeObject(scopeName string) (*ScopeObject, error) {
+	defer b.lock.Unlock()
+	b.lock.Lock()
 	_, ok := b.scopes[scopeName]
 	if ok {
 		return b.scopes[scopeName], nil
@@ -26,3 +33,18 @@ func (b *BucketObject) getScopeObject(scopeName string) (*ScopeObject, error) {
 	}
 
 }
+
+// removeScopeObject removes a scope and its collections so that they are opened again by the next task using them.
+func (b *BucketObject) removeScopeObject(scopeName string) {
+	defer b.lock.Unlock()
+	b.lock.Lock()
+	delete(b.scopes, scopeName)
+}
+
+// cachedScopeObject returns the scope if it has been opened, without opening it.
+func (b *BucketObject) cachedScopeObject(scopeName string) (*ScopeObject, bool) {
+	defer b.lock.Unlock()
+	b.lock.Lock()
+	s, ok := b.scopes[scopeName]
+	return s, ok
+}
@@ -0,0 +1,55 @@
+package cb_sdk
+
+import (
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+)
+
+// CasOptions makes mutations CAS conditional. The current CAS of a document is read and the document is replaced only
+// if it still has that CAS. ConflictRate is the fraction of documents, between 0 and 1, that are mutated again between
+// reading the CAS and replacing them so that the replace fails with a CAS mismatch.
+type CasOptions struct {
+	ConflictRate float64 `json:"conflictRate,omitempty" doc:"true"`
+}
+
+// ConfigCasOptions validates the CasOptions.
+func ConfigCasOptions(c *CasOptions) error {
+	if c == nil {
+		return task_errors.ErrParsingCasOptions
+	}
+	if c.ConflictRate < 0 || c.ConflictRate > 1 {
+		return fmt.Errorf("%w : conflictRate %v is not between 0 and 1", task_errors.ErrParsingCasOptions,
+			c.ConflictRate)
+	}
+	return nil
+}
+
+// ReplaceWithCas reads the current CAS of a document and replaces the document if it still has that CAS. If
+// injectConflict is set the document is first replaced by its current content, preserving its expiry, which changes
+// its CAS and makes the CAS guarded replace fail with gocb.ErrCasMismatch.
+func ReplaceWithCas(collection *gocb.Collection, docId string, doc interface{}, options gocb.ReplaceOptions,
+	injectConflict bool) (*gocb.MutationResult, error) {
+	getResult, err := collection.Get(docId, &gocb.GetOptions{
+		Timeout: options.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if injectConflict {
+		var current interface{}
+		if err := getResult.Content(&current); err != nil {
+			return nil, err
+		}
+		if _, err := collection.Replace(docId, current, &gocb.ReplaceOptions{
+			Timeout:        options.Timeout,
+			PreserveExpiry: true,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	options.Cas = getResult.Cas()
+	return collection.Replace(docId, doc, &options)
+}
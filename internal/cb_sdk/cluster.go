@@ -5,16 +5,26 @@ import (
 	"github.com/couchbase/gocb/v2"
 	"github.com/couchbaselabs/sirius/internal/task_errors"
 	"log"
+	"sync"
 	"time"
 )
 
 const WaitUnityReadyTime = 10
-const WaitUntilReadyTimeRetries = 5
+
+// BucketRetryInterval is the time in seconds for which a bucket that failed to be ready is not opened again, so that
+// the tasks using it fail promptly instead of each waiting for it.
+const BucketRetryInterval = 5
 
 type TimeoutsConfig struct {
-	ConnectTimeout   int `json:"connectTimeout,omitempty" doc:"true"`
-	KVTimeout        int `json:"KVTimeout,omitempty" doc:"true"`
-	KVDurableTimeout int `json:"KVDurableTimeout,omitempty" doc:"true"`
+	ConnectTimeout    int `json:"connectTimeout,omitempty" doc:"true"`
+	KVTimeout         int `json:"KVTimeout,omitempty" doc:"true"`
+	KVDurableTimeout  int `json:"KVDurableTimeout,omitempty" doc:"true"`
+	KVScanTimeout     int `json:"KVScanTimeout,omitempty" doc:"true"`
+	ViewTimeout       int `json:"viewTimeout,omitempty" doc:"true"`
+	QueryTimeout      int `json:"queryTimeout,omitempty" doc:"true"`
+	AnalyticsTimeout  int `json:"analyticsTimeout,omitempty" doc:"true"`
+	SearchTimeout     int `json:"searchTimeout,omitempty" doc:"true"`
+	ManagementTimeout int `json:"managementTimeout,omitempty" doc:"true"`
 }
 
 type CompressionConfig struct {
@@ -26,11 +36,21 @@ type CompressionConfig struct {
 }
 
 type ClusterConfig struct {
-	Username          string            `json:"username" doc:"true"`
-	Password          string            `json:"password" doc:"true"`
-	ConnectionString  string            `json:"connectionString" doc:"true"`
-	CompressionConfig CompressionConfig `json:"compressionConfig,omitempty" doc:"true"`
-	TimeoutsConfig    TimeoutsConfig    `json:"timeoutsConfig,omitempty" doc:"true"`
+	Username               string                 `json:"username" doc:"true"`
+	Password               string                 `json:"password" doc:"true"`
+	ConnectionString       string                 `json:"connectionString" doc:"true"`
+	CompressionConfig      CompressionConfig      `json:"compressionConfig,omitempty" doc:"true"`
+	TimeoutsConfig         TimeoutsConfig         `json:"timeoutsConfig,omitempty" doc:"true"`
+	SecurityConfig         SecurityConfig         `json:"securityConfig,omitempty" doc:"true"`
+	CircuitBreakerConfig   CircuitBreakerConfig   `json:"circuitBreakerConfig,omitempty" doc:"true"`
+	OrphanReporterConfig   OrphanReporterConfig   `json:"orphanReporterConfig,omitempty" doc:"true"`
+	ThresholdLoggingConfig ThresholdLoggingConfig `json:"thresholdLoggingConfig,omitempty" doc:"true"`
+	RetryStrategyConfig    RetryStrategyConfig    `json:"retryStrategyConfig,omitempty" doc:"true"`
+	IoConfig               IoConfig               `json:"ioConfig,omitempty" doc:"true"`
+	// NetworkType selects the addresses used to connect, default, external for alternate addresses or auto.
+	NetworkType          string `json:"networkType,omitempty" doc:"true"`
+	KVPoolSize           int    `json:"kvPoolSize,omitempty" doc:"true"`
+	ConnectionBufferSize uint   `json:"connectionBufferSize,omitempty" doc:"true"`
 }
 
 func ValidateClusterConfig(c *ClusterConfig) error {
@@ -40,7 +60,10 @@ func ValidateClusterConfig(c *ClusterConfig) error {
 	if c.ConnectionString == "" {
 		return task_errors.ErrInvalidConnectionString
 	}
-	if c.Username == "" || c.Password == "" {
+	if err := c.SecurityConfig.validate(c.ConnectionString); err != nil {
+		return err
+	}
+	if !c.SecurityConfig.UsesClientCertificate() && (c.Username == "" || c.Password == "") {
 		return fmt.Errorf("connection string : %s | %w", c.ConnectionString, task_errors.ErrCredentialMissing)
 	}
 	return nil
@@ -49,40 +72,122 @@ func ValidateClusterConfig(c *ClusterConfig) error {
 type ClusterObject struct {
 	Cluster *gocb.Cluster            `json:"-"`
 	Buckets map[string]*BucketObject `json:"-"`
+	Health  ConnectionHealth         `json:"-"`
+	pooled  *pooledCluster
+	poolKey string
+	// lock guards Buckets and the buckets being opened or failed to be ready.
+	lock           sync.Mutex
+	bucketCalls    map[string]*bucketCall
+	bucketFailures map[string]bucketFailure
+}
+
+// bucketCall is a bucket being opened, waited on by every task using the bucket meanwhile.
+type bucketCall struct {
+	done   chan struct{}
+	bucket *BucketObject
+	err    error
+}
+
+// bucketFailure is the last failure of a bucket to be ready.
+type bucketFailure struct {
+	err error
+	at  time.Time
 }
 
 func (c *ClusterObject) setBucketObject(bucketName string, b *BucketObject) {
 	c.Buckets[bucketName] = b
 }
 
+// getBucketObject returns the BucketObject of an opened bucket. Otherwise, it opens the bucket without holding the
+// lock, so that only the tasks using the same bucket wait for it to be ready.
 func (c *ClusterObject) getBucketObject(bucketName string) (*BucketObject, error) {
-	_, ok := c.Buckets[bucketName]
-
-	if !ok {
-		bucket := c.Cluster.Bucket(bucketName)
-		var waitUntilReadyError error
-		for i := 0; i < WaitUntilReadyTimeRetries; i++ {
-			if waitUntilReadyError = bucket.WaitUntilReady(WaitUnityReadyTime*time.Second,
-				nil); waitUntilReadyError == nil {
-				break
-			}
-			log.Println("retrying bucket WaitUntilReady")
-		}
+	c.lock.Lock()
+	if b, ok := c.Buckets[bucketName]; ok {
+		c.lock.Unlock()
+		return b, nil
+	}
+	if f, ok := c.bucketFailures[bucketName]; ok && time.Since(f.at) < BucketRetryInterval*time.Second {
+		c.lock.Unlock()
+		return nil, f.err
+	}
+	if call, ok := c.bucketCalls[bucketName]; ok {
+		c.lock.Unlock()
+		<-call.done
+		return call.bucket, call.err
+	}
+	if c.bucketCalls == nil {
+		c.bucketCalls = make(map[string]*bucketCall)
+	}
+	call := &bucketCall{done: make(chan struct{})}
+	c.bucketCalls[bucketName] = call
+	c.lock.Unlock()
 
-		if waitUntilReadyError != nil {
-			return nil, waitUntilReadyError
-		}
+	call.bucket, call.err = c.openBucket(bucketName)
 
-		b := &BucketObject{
-			bucket: bucket,
-			scopes: make(map[string]*ScopeObject),
+	c.lock.Lock()
+	delete(c.bucketCalls, bucketName)
+	if call.err == nil {
+		c.setBucketObject(bucketName, call.bucket)
+		delete(c.bucketFailures, bucketName)
+	} else {
+		if c.bucketFailures == nil {
+			c.bucketFailures = make(map[string]bucketFailure)
 		}
-		c.setBucketObject(bucketName, b)
+		c.bucketFailures[bucketName] = bucketFailure{err: call.err, at: time.Now()}
 	}
+	c.lock.Unlock()
+	close(call.done)
 
-	return c.Buckets[bucketName], nil
+	return call.bucket, call.err
 }
 
+// openBucket opens a bucket and waits for it to be ready.
+func (c *ClusterObject) openBucket(bucketName string) (*BucketObject, error) {
+	if c.pooled != nil {
+		c.pooled.bucketLock.Lock()
+	}
+	bucket := c.Cluster.Bucket(bucketName)
+	if c.pooled != nil {
+		c.pooled.bucketLock.Unlock()
+	}
+	if err := bucket.WaitUntilReady(WaitUnityReadyTime*time.Second, nil); err != nil {
+		log.Println("bucket", bucketName, "is not ready", err.Error())
+		return nil, fmt.Errorf("bucket %s is not ready : %w", bucketName, err)
+	}
+	return &BucketObject{
+		bucket: bucket,
+		scopes: make(map[string]*ScopeObject),
+		Health: ConnectionHealth{Healthy: true, LastChecked: time.Now()},
+	}, nil
+}
+
+// removeBucketObject removes a bucket so that it is opened again by the next task using it.
+func (c *ClusterObject) removeBucketObject(bucketName string) {
+	defer c.lock.Unlock()
+	c.lock.Lock()
+	delete(c.Buckets, bucketName)
+	delete(c.bucketFailures, bucketName)
+}
+
+// cachedBucketObject returns the bucket if it has been opened, without opening it.
+func (c *ClusterObject) cachedBucketObject(bucketName string) (*BucketObject, bool) {
+	defer c.lock.Unlock()
+	c.lock.Lock()
+	b, ok := c.Buckets[bucketName]
+	return b, ok
+}
+
+// reusable returns true if the cluster connection was opened with the cluster config of poolKey and was not evicted
+// from the connection pool.
+func (c *ClusterObject) reusable(poolKey string) bool {
+	return c.poolKey == poolKey && (c.pooled == nil || !connectionPool.isEvicted(c.pooled))
+}
+
+// Close releases the cluster connection, which is closed once no request is using it.
 func Close(c *ClusterObject) {
+	if c.pooled != nil {
+		connectionPool.release(c.poolKey, c.pooled)
+		return
+	}
 	_ = c.Cluster.Close(nil)
 }
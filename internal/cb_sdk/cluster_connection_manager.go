@@ -10,10 +10,12 @@ import (
 	"time"
 )
 
-// ConnectionManager contains different cluster information and connections to them.
+// ConnectionManager contains different cluster information and connections to them. The lock only guards the
+// lookup of Clusters, buckets are opened under the lock of their ClusterObject so that a slow bucket does not block
+// the tasks using other buckets.
 type ConnectionManager struct {
 	Clusters map[string]*ClusterObject
-	lock     sync.Mutex
+	lock     sync.RWMutex
 }
 
 // ConfigConnectionManager returns an instance of ConnectionManager.
@@ -21,26 +23,18 @@ func ConfigConnectionManager() *ConnectionManager {
 
 	return &ConnectionManager{
 		Clusters: make(map[string]*ClusterObject),
-		lock:     sync.Mutex{},
+		lock:     sync.RWMutex{},
 	}
 }
 
-func addKVPoolSize(connStr string) string {
-	if !strings.Contains(connStr, "kv_pool_size") {
-		if !strings.Contains(connStr, "?") {
-			return connStr + "?kv_pool_size=32"
-		}
-	}
-	return connStr
-}
-
-// DisconnectAll disconnect all the Clusters used in a tasks.Request
+// DisconnectAll releases all the Clusters used in a tasks.Request. A cluster connection shared with other requests
+// stays open until released by all of them.
 func (cm *ConnectionManager) DisconnectAll() {
 	defer cm.lock.Unlock()
 	cm.lock.Lock()
 	for cS, v := range cm.Clusters {
 		if v.Cluster != nil {
-			_ = v.Cluster.Close(nil)
+			Close(v)
 			delete(cm.Clusters, cS)
 		}
 		v = nil
@@ -74,8 +68,8 @@ func GetClusterIdentifier(connStr string) (string, error) {
 	}
 }
 
-// getClusterObject returns ClusterObject if cluster is already setup.
-// If not, then set up a ClusterObject using ClusterConfig.
+// getClusterObject returns ClusterObject if cluster is already setup with the same connection string, credentials
+// and options, as per the key of the ConnectionPool. If not, then set up a ClusterObject using ClusterConfig.
 func (cm *ConnectionManager) getClusterObject(clusterConfig *ClusterConfig) (*ClusterObject, error) {
 
 	if clusterConfig == nil {
@@ -87,60 +81,58 @@ func (cm *ConnectionManager) getClusterObject(clusterConfig *ClusterConfig) (*Cl
 		return nil, err
 	}
 
-	clusterConfig.ConnectionString = addKVPoolSize(clusterConfig.ConnectionString)
+	if connStr := clusterConfig.connectionStringWithOptions(); connStr != clusterConfig.ConnectionString {
+		clusterConfig.ConnectionString = connStr
+	}
 
-	_, ok := cm.Clusters[clusterIdentifier]
-	if !ok {
-		if err := ValidateClusterConfig(clusterConfig); err != nil {
-			return nil, err
-		}
-		cluster, err := gocb.Connect(clusterConfig.ConnectionString, gocb.ClusterOptions{
-			Authenticator: gocb.PasswordAuthenticator{
-				Username: clusterConfig.Username,
-				Password: clusterConfig.Password,
-			},
-			Username: clusterConfig.Username,
-			Password: clusterConfig.Password,
-			TimeoutsConfig: gocb.TimeoutsConfig{
-				ConnectTimeout:   time.Duration(clusterConfig.TimeoutsConfig.ConnectTimeout) * time.Second,
-				KVTimeout:        time.Duration(clusterConfig.TimeoutsConfig.KVTimeout) * time.Second,
-				KVDurableTimeout: time.Duration(clusterConfig.TimeoutsConfig.KVDurableTimeout) * time.Second,
-			},
-			CircuitBreakerConfig: gocb.CircuitBreakerConfig{
-				Disabled: true,
-			},
-			CompressionConfig: gocb.CompressionConfig{
-				Disabled: clusterConfig.CompressionConfig.Disabled,
-				MinSize:  clusterConfig.CompressionConfig.MinSize,
-				MinRatio: clusterConfig.CompressionConfig.MinRatio,
-			},
-			SecurityConfig: gocb.SecurityConfig{
-				TLSSkipVerify: true,
-			},
-			InternalConfig: gocb.InternalConfig{
-				ConnectionBufferSize: 1048576,
-			},
-		})
-		if err != nil {
-			return nil, err
-		}
+	poolKey, err := clusterPoolKey(clusterConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	cm.lock.RLock()
+	c, ok := cm.Clusters[clusterIdentifier]
+	cm.lock.RUnlock()
+	if ok && c.reusable(poolKey) {
+		return c, nil
+	}
 
-		c := &ClusterObject{
-			Cluster: cluster,
-			Buckets: make(map[string]*BucketObject),
+	defer cm.lock.Unlock()
+	cm.lock.Lock()
+	if c, ok := cm.Clusters[clusterIdentifier]; ok {
+		if c.reusable(poolKey) {
+			return c, nil
 		}
-		cm.setClusterObject(clusterIdentifier, c)
+		// the credentials or options used for the cluster changed, or the shared connection was evicted by the
+		// health check of another request, so the connection is released and opened again. Tasks of the request
+		// still running on the previous connection keep using it until it is closed by the last request releasing
+		// it, which fails their remaining operations.
+		Close(c)
+		delete(cm.Clusters, clusterIdentifier)
+	}
+	if err := ValidateClusterConfig(clusterConfig); err != nil {
+		return nil, err
+	}
+	pooled, poolKey, err := connectionPool.acquire(clusterConfig)
+	if err != nil {
+		return nil, err
 	}
 
-	return cm.Clusters[clusterIdentifier], nil
+	c = &ClusterObject{
+		Cluster: pooled.cluster,
+		Buckets: make(map[string]*BucketObject),
+		Health:  ConnectionHealth{Healthy: true, LastChecked: time.Now()},
+		pooled:  pooled,
+		poolKey: poolKey,
+	}
+	cm.setClusterObject(clusterIdentifier, c)
+	return c, nil
 }
 
 // GetCollection return a *gocb.Collection which represents a single Collection.
 func (cm *ConnectionManager) GetCollection(clusterConfig *ClusterConfig, bucketName, scopeName,
 	collectionName string) (*CollectionObject,
 	error) {
-	defer cm.lock.Unlock()
-	cm.lock.Lock()
 	cObj, err1 := cm.getClusterObject(clusterConfig)
 	if err1 != nil {
 		return nil, err1
@@ -163,8 +155,6 @@ func (cm *ConnectionManager) GetCollection(clusterConfig *ClusterConfig, bucketN
 // GetScope return a *gocb.Scope which represents  a single scope within a bucket.
 func (cm *ConnectionManager) GetScope(clusterConfig *ClusterConfig, bucketName, scopeName string) (*gocb.Scope,
 	error) {
-	defer cm.lock.Unlock()
-	cm.lock.Lock()
 	cObj, err1 := cm.getClusterObject(clusterConfig)
 	if err1 != nil {
 		return nil, err1
@@ -183,8 +173,6 @@ func (cm *ConnectionManager) GetScope(clusterConfig *ClusterConfig, bucketName,
 // GetBucket return a *gocb.Bucket which represents a single bucket within a Cluster.
 func (cm *ConnectionManager) GetBucket(clusterConfig *ClusterConfig, bucketName string) (*gocb.Bucket,
 	error) {
-	defer cm.lock.Unlock()
-	cm.lock.Lock()
 	cObj, err1 := cm.getClusterObject(clusterConfig)
 	if err1 != nil {
 		return nil, err1
@@ -199,11 +187,61 @@ func (cm *ConnectionManager) GetBucket(clusterConfig *ClusterConfig, bucketName
 // GetCluster return a *gocb.Cluster which represents connection to a specific Couchbase Cluster.
 func (cm *ConnectionManager) GetCluster(clusterConfig *ClusterConfig) (*gocb.Cluster,
 	error) {
-	defer cm.lock.Unlock()
-	cm.lock.Lock()
 	cObj, err1 := cm.getClusterObject(clusterConfig)
 	if err1 != nil {
 		return nil, err1
 	}
 	return cObj.Cluster, nil
 }
+
+// RemoveBucket removes the bucket and its scopes and collections from the connection manager so that a
+// bucket created again with the same name is reopened.
+func (cm *ConnectionManager) RemoveBucket(clusterConfig *ClusterConfig, bucketName string) {
+	clusterIdentifier, err := GetClusterIdentifier(clusterConfig.ConnectionString)
+	if err != nil {
+		return
+	}
+	cm.lock.RLock()
+	cObj, ok := cm.Clusters[clusterIdentifier]
+	cm.lock.RUnlock()
+	if ok {
+		cObj.removeBucketObject(bucketName)
+	}
+}
+
+// RemoveScope removes the scope and its collections from the connection manager so that a scope created again
+// with the same name is reopened.
+func (cm *ConnectionManager) RemoveScope(clusterConfig *ClusterConfig, bucketName, scopeName string) {
+	if bObj, ok := cm.cachedBucketObject(clusterConfig, bucketName); ok {
+		bObj.removeScopeObject(scopeName)
+	}
+}
+
+// RemoveCollection removes the collection from the connection manager so that a collection created again with
+// the same name is reopened.
+func (cm *ConnectionManager) RemoveCollection(clusterConfig *ClusterConfig, bucketName, scopeName,
+	collectionName string) {
+	bObj, ok := cm.cachedBucketObject(clusterConfig, bucketName)
+	if !ok {
+		return
+	}
+	if sObj, ok := bObj.cachedScopeObject(scopeName); ok {
+		sObj.removeCollection(collectionName)
+	}
+}
+
+// cachedBucketObject returns the bucket of a cluster if it has been opened, without connecting to the cluster.
+func (cm *ConnectionManager) cachedBucketObject(clusterConfig *ClusterConfig, bucketName string) (*BucketObject,
+	bool) {
+	clusterIdentifier, err := GetClusterIdentifier(clusterConfig.ConnectionString)
+	if err != nil {
+		return nil, false
+	}
+	cm.lock.RLock()
+	cObj, ok := cm.Clusters[clusterIdentifier]
+	cm.lock.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return cObj.cachedBucketObject(bucketName)
+}
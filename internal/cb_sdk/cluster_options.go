@@ -0,0 +1,239 @@
+package cb_sdk
+
+import (
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultKVPoolSize           = 32
+	DefaultConnectionBufferSize = 1048576
+	BestEffortRetryStrategy     = "bestEffort"
+	FailFastRetryStrategy       = "failFast"
+)
+
+// CircuitBreakerConfig configures the circuit breakers of the SDK, which are disabled unless enabled is set.
+// Windows and timeouts are in seconds.
+type CircuitBreakerConfig struct {
+	Enabled                  bool    `json:"enabled,omitempty" doc:"true"`
+	VolumeThreshold          int64   `json:"volumeThreshold,omitempty" doc:"true"`
+	ErrorThresholdPercentage float64 `json:"errorThresholdPercentage,omitempty" doc:"true"`
+	SleepWindow              int     `json:"sleepWindow,omitempty" doc:"true"`
+	RollingWindow            int     `json:"rollingWindow,omitempty" doc:"true"`
+	CanaryTimeout            int     `json:"canaryTimeout,omitempty" doc:"true"`
+}
+
+// OrphanReporterConfig configures the logging of responses received for requests which already timed out.
+// ReportInterval is in seconds.
+type OrphanReporterConfig struct {
+	Disabled       bool   `json:"disabled,omitempty" doc:"true"`
+	ReportInterval int    `json:"reportInterval,omitempty" doc:"true"`
+	SampleSize     uint32 `json:"sampleSize,omitempty" doc:"true"`
+}
+
+// ThresholdLoggingConfig configures the logging of operations slower than the threshold of their service.
+// Interval is in seconds and thresholds are in milliseconds.
+type ThresholdLoggingConfig struct {
+	Interval            int    `json:"interval,omitempty" doc:"true"`
+	SampleSize          uint32 `json:"sampleSize,omitempty" doc:"true"`
+	KVThreshold         int    `json:"KVThreshold,omitempty" doc:"true"`
+	KVScanThreshold     int    `json:"KVScanThreshold,omitempty" doc:"true"`
+	ViewsThreshold      int    `json:"viewsThreshold,omitempty" doc:"true"`
+	QueryThreshold      int    `json:"queryThreshold,omitempty" doc:"true"`
+	SearchThreshold     int    `json:"searchThreshold,omitempty" doc:"true"`
+	AnalyticsThreshold  int    `json:"analyticsThreshold,omitempty" doc:"true"`
+	ManagementThreshold int    `json:"managementThreshold,omitempty" doc:"true"`
+}
+
+// RetryStrategyConfig configures how the SDK retries operations. bestEffort retries until the operation times out,
+// waiting minBackoff milliseconds multiplied by backoffFactor on every attempt up to maxBackoff milliseconds, and
+// failFast does not retry.
+type RetryStrategyConfig struct {
+	Strategy      string  `json:"strategy,omitempty" doc:"true"`
+	MinBackoff    int     `json:"minBackoff,omitempty" doc:"true"`
+	MaxBackoff    int     `json:"maxBackoff,omitempty" doc:"true"`
+	BackoffFactor float64 `json:"backoffFactor,omitempty" doc:"true"`
+}
+
+// IoConfig configures the mutation tokens and server durations returned by the server.
+type IoConfig struct {
+	DisableMutationTokens  bool `json:"disableMutationTokens,omitempty" doc:"true"`
+	DisableServerDurations bool `json:"disableServerDurations,omitempty" doc:"true"`
+}
+
+// failFastRetryStrategy never retries an operation.
+type failFastRetryStrategy struct{}
+
+func (failFastRetryStrategy) RetryAfter(_ gocb.RetryRequest, _ gocb.RetryReason) gocb.RetryAction {
+	return &gocb.NoRetryRetryAction{}
+}
+
+// exponentialBackoff returns a backoff of min * factor^attempts, capped at max.
+func exponentialBackoff(min, max time.Duration, factor float64) gocb.BackoffCalculator {
+	return func(retryAttempts uint32) time.Duration {
+		backoff := float64(min) * math.Pow(factor, float64(retryAttempts))
+		if backoff > float64(max) || math.IsInf(backoff, 0) {
+			return max
+		}
+		return time.Duration(backoff)
+	}
+}
+
+// retryStrategy returns the gocb.RetryStrategy, nil being the default best effort strategy of the SDK.
+func (r *RetryStrategyConfig) retryStrategy() (gocb.RetryStrategy, error) {
+	if r.MinBackoff < 0 || r.MaxBackoff < 0 || r.BackoffFactor < 0 {
+		return nil, fmt.Errorf("%w : backoff cannot be negative", task_errors.ErrInvalidClusterOptions)
+	}
+	switch r.Strategy {
+	case "", BestEffortRetryStrategy:
+		if r.MinBackoff == 0 && r.MaxBackoff == 0 && r.BackoffFactor == 0 {
+			return nil, nil
+		}
+		min, max, factor := time.Duration(r.MinBackoff)*time.Millisecond, time.Duration(r.MaxBackoff)*time.Millisecond,
+			r.BackoffFactor
+		if min == 0 {
+			min = time.Millisecond
+		}
+		if max == 0 {
+			max = 500 * time.Millisecond
+		}
+		if factor == 0 {
+			factor = 2
+		}
+		if min > max {
+			return nil, fmt.Errorf("%w : minBackoff is greater than maxBackoff", task_errors.ErrInvalidClusterOptions)
+		}
+		return gocb.NewBestEffortRetryStrategy(exponentialBackoff(min, max, factor)), nil
+	case FailFastRetryStrategy:
+		return failFastRetryStrategy{}, nil
+	}
+	return nil, fmt.Errorf("%w : unknown retry strategy %s", task_errors.ErrInvalidClusterOptions, r.Strategy)
+}
+
+// tracer returns a gocb.ThresholdLoggingTracer if any threshold logging option is set, and nil for the default
+// tracer of the SDK.
+func (t *ThresholdLoggingConfig) tracer() gocb.RequestTracer {
+	if *t == (ThresholdLoggingConfig{}) {
+		return nil
+	}
+	ms := func(v int) time.Duration {
+		return time.Duration(v) * time.Millisecond
+	}
+	return gocb.NewThresholdLoggingTracer(&gocb.ThresholdLoggingOptions{
+		Interval:            time.Duration(t.Interval) * time.Second,
+		SampleSize:          t.SampleSize,
+		KVThreshold:         ms(t.KVThreshold),
+		KVScanThreshold:     ms(t.KVScanThreshold),
+		ViewsThreshold:      ms(t.ViewsThreshold),
+		QueryThreshold:      ms(t.QueryThreshold),
+		SearchThreshold:     ms(t.SearchThreshold),
+		AnalyticsThreshold:  ms(t.AnalyticsThreshold),
+		ManagementThreshold: ms(t.ManagementThreshold),
+	})
+}
+
+// connectionStringWithOptions adds the kv pool size and network type to the connection string unless it already
+// has them.
+func (c *ClusterConfig) connectionStringWithOptions() string {
+	connStr := c.ConnectionString
+	addOption := func(key string, value string) {
+		if strings.Contains(connStr, key+"=") {
+			return
+		}
+		if strings.Contains(connStr, "?") {
+			connStr += "&"
+		} else {
+			connStr += "?"
+		}
+		connStr += key + "=" + value
+	}
+	kvPoolSize := c.KVPoolSize
+	if kvPoolSize == 0 {
+		kvPoolSize = DefaultKVPoolSize
+	}
+	addOption("kv_pool_size", fmt.Sprintf("%d", kvPoolSize))
+	if c.NetworkType != "" {
+		addOption("network", c.NetworkType)
+	}
+	return connStr
+}
+
+// clusterOptions returns the gocb.ClusterOptions for connecting to the cluster.
+func (c *ClusterConfig) clusterOptions() (gocb.ClusterOptions, error) {
+	if c.KVPoolSize < 0 {
+		return gocb.ClusterOptions{}, fmt.Errorf("%w : kvPoolSize cannot be negative",
+			task_errors.ErrInvalidClusterOptions)
+	}
+	switch c.NetworkType {
+	case "", "default", "external", "auto":
+	default:
+		return gocb.ClusterOptions{}, fmt.Errorf("%w : unknown network type %s", task_errors.ErrInvalidClusterOptions,
+			c.NetworkType)
+	}
+	authenticator, err := c.authenticator()
+	if err != nil {
+		return gocb.ClusterOptions{}, err
+	}
+	securityConfig, err := c.SecurityConfig.gocbSecurityConfig()
+	if err != nil {
+		return gocb.ClusterOptions{}, err
+	}
+	retryStrategy, err := c.RetryStrategyConfig.retryStrategy()
+	if err != nil {
+		return gocb.ClusterOptions{}, err
+	}
+	connectionBufferSize := c.ConnectionBufferSize
+	if connectionBufferSize == 0 {
+		connectionBufferSize = DefaultConnectionBufferSize
+	}
+	seconds := func(v int) time.Duration {
+		return time.Duration(v) * time.Second
+	}
+
+	return gocb.ClusterOptions{
+		Authenticator: authenticator,
+		TimeoutsConfig: gocb.TimeoutsConfig{
+			ConnectTimeout:    seconds(c.TimeoutsConfig.ConnectTimeout),
+			KVTimeout:         seconds(c.TimeoutsConfig.KVTimeout),
+			KVDurableTimeout:  seconds(c.TimeoutsConfig.KVDurableTimeout),
+			KVScanTimeout:     seconds(c.TimeoutsConfig.KVScanTimeout),
+			ViewTimeout:       seconds(c.TimeoutsConfig.ViewTimeout),
+			QueryTimeout:      seconds(c.TimeoutsConfig.QueryTimeout),
+			AnalyticsTimeout:  seconds(c.TimeoutsConfig.AnalyticsTimeout),
+			SearchTimeout:     seconds(c.TimeoutsConfig.SearchTimeout),
+			ManagementTimeout: seconds(c.TimeoutsConfig.ManagementTimeout),
+		},
+		RetryStrategy: retryStrategy,
+		Tracer:        c.ThresholdLoggingConfig.tracer(),
+		OrphanReporterConfig: gocb.OrphanReporterConfig{
+			Disabled:       c.OrphanReporterConfig.Disabled,
+			ReportInterval: seconds(c.OrphanReporterConfig.ReportInterval),
+			SampleSize:     c.OrphanReporterConfig.SampleSize,
+		},
+		CircuitBreakerConfig: gocb.CircuitBreakerConfig{
+			Disabled:                 !c.CircuitBreakerConfig.Enabled,
+			VolumeThreshold:          c.CircuitBreakerConfig.VolumeThreshold,
+			ErrorThresholdPercentage: c.CircuitBreakerConfig.ErrorThresholdPercentage,
+			SleepWindow:              seconds(c.CircuitBreakerConfig.SleepWindow),
+			RollingWindow:            seconds(c.CircuitBreakerConfig.RollingWindow),
+			CanaryTimeout:            seconds(c.CircuitBreakerConfig.CanaryTimeout),
+		},
+		IoConfig: gocb.IoConfig{
+			DisableMutationTokens:  c.IoConfig.DisableMutationTokens,
+			DisableServerDurations: c.IoConfig.DisableServerDurations,
+		},
+		CompressionConfig: gocb.CompressionConfig{
+			Disabled: c.CompressionConfig.Disabled,
+			MinSize:  c.CompressionConfig.MinSize,
+			MinRatio: c.CompressionConfig.MinRatio,
+		},
+		SecurityConfig: securityConfig,
+		InternalConfig: gocb.InternalConfig{
+			ConnectionBufferSize: connectionBufferSize,
+		},
+	}, nil
+}
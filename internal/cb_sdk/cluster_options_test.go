@@ -0,0 +1,73 @@
+package cb_sdk
+
+import (
+	"errors"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"testing"
+	"time"
+)
+
+func TestConnectionStringWithOptions(t *testing.T) {
+	for expected, c := range map[string]*ClusterConfig{
+		"couchbase://host?kv_pool_size=32":                   {ConnectionString: "couchbase://host"},
+		"couchbase://host?kv_pool_size=4":                    {ConnectionString: "couchbase://host?kv_pool_size=4", KVPoolSize: 8},
+		"couchbase://host?kv_pool_size=8&network=external":   {ConnectionString: "couchbase://host", KVPoolSize: 8, NetworkType: "external"},
+		"couchbases://host?network=auto&kv_pool_size=32":     {ConnectionString: "couchbases://host?network=auto", NetworkType: "default"},
+		"couchbase://host?compression=false&kv_pool_size=32": {ConnectionString: "couchbase://host?compression=false"},
+	} {
+		if connStr := c.connectionStringWithOptions(); connStr != expected {
+			t.Fatalf("expected %s, got %s", expected, connStr)
+		}
+	}
+}
+
+func TestClusterOptions(t *testing.T) {
+	c := &ClusterConfig{ConnectionString: "couchbase://host", Username: "u", Password: "p"}
+	options, err := c.clusterOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !options.CircuitBreakerConfig.Disabled || options.RetryStrategy != nil || options.Tracer != nil ||
+		options.InternalConfig.ConnectionBufferSize != DefaultConnectionBufferSize {
+		t.Fatalf("unexpected default options %+v", options)
+	}
+
+	c.TimeoutsConfig.QueryTimeout = 120
+	c.CircuitBreakerConfig = CircuitBreakerConfig{Enabled: true, VolumeThreshold: 10, SleepWindow: 2}
+	c.ThresholdLoggingConfig.KVThreshold = 100
+	c.RetryStrategyConfig.Strategy = FailFastRetryStrategy
+	c.ConnectionBufferSize = 1024
+	if options, err = c.clusterOptions(); err != nil {
+		t.Fatal(err)
+	}
+	if options.TimeoutsConfig.QueryTimeout != 2*time.Minute || options.CircuitBreakerConfig.Disabled ||
+		options.CircuitBreakerConfig.SleepWindow != 2*time.Second || options.InternalConfig.ConnectionBufferSize != 1024 {
+		t.Fatalf("unexpected options %+v", options)
+	}
+	if tracer, ok := options.Tracer.(*gocb.ThresholdLoggingTracer); !ok || tracer.KVThreshold != 100*time.Millisecond {
+		t.Fatalf("unexpected tracer %T", options.Tracer)
+	}
+	if _, ok := options.RetryStrategy.RetryAfter(nil, nil).(*gocb.NoRetryRetryAction); !ok {
+		t.Fatal("expected fail fast retry strategy")
+	}
+
+	for _, invalid := range []*ClusterConfig{
+		{NetworkType: "internal"},
+		{KVPoolSize: -1},
+		{RetryStrategyConfig: RetryStrategyConfig{Strategy: "always"}},
+		{RetryStrategyConfig: RetryStrategyConfig{MinBackoff: 100, MaxBackoff: 10}},
+	} {
+		if _, err := invalid.clusterOptions(); !errors.Is(err, task_errors.ErrInvalidClusterOptions) {
+			t.Fatalf("expected invalid cluster options for %+v, got %v", invalid, err)
+		}
+	}
+
+	backoff := exponentialBackoff(time.Millisecond, 10*time.Millisecond, 2)
+	for attempts, expected := range map[uint32]time.Duration{0: time.Millisecond, 2: 4 * time.Millisecond,
+		10: 10 * time.Millisecond} {
+		if d := backoff(attempts); d != expected {
+			t.Fatalf("attempt %d : expected %v, got %v", attempts, expected, d)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package cb_sdk
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetBucketObject(t *testing.T) {
+	opened := &BucketObject{scopes: make(map[string]*ScopeObject)}
+	c := &ClusterObject{Buckets: map[string]*BucketObject{"opened": opened}}
+	if b, err := c.getBucketObject("opened"); err != nil || b != opened {
+		t.Fatalf("expected the opened bucket, got %v %v", b, err)
+	}
+
+	// a bucket which recently failed to be ready fails without being opened again.
+	notReady := errors.New("bucket not ready")
+	c.bucketFailures = map[string]bucketFailure{"failed": {err: notReady, at: time.Now()}}
+	if _, err := c.getBucketObject("failed"); !errors.Is(err, notReady) {
+		t.Fatalf("expected the last failure, got %v", err)
+	}
+
+	// tasks using a bucket being opened wait for it, while other buckets are not blocked.
+	call := &bucketCall{done: make(chan struct{})}
+	c.bucketCalls = map[string]*bucketCall{"opening": call}
+	var wg sync.WaitGroup
+	results := make([]*BucketObject, 4)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = c.getBucketObject("opening")
+		}(i)
+	}
+	if b, err := c.getBucketObject("opened"); err != nil || b != opened {
+		t.Fatalf("expected the opened bucket while another is opening, got %v %v", b, err)
+	}
+	call.bucket = &BucketObject{scopes: make(map[string]*ScopeObject)}
+	close(call.done)
+	wg.Wait()
+	for _, b := range results {
+		if b != call.bucket {
+			t.Fatal("expected every waiting task to get the opened bucket")
+		}
+	}
+
+	// dropped scopes and collections are removed from an opened bucket.
+	opened.scopes["scope"] = &ScopeObject{collections: map[string]*CollectionObject{"collection": {}, "other": {}}}
+	opened.scopes["dropped"] = &ScopeObject{collections: make(map[string]*CollectionObject)}
+	opened.removeScopeObject("dropped")
+	if _, ok := opened.cachedScopeObject("dropped"); ok {
+		t.Fatal("expected the scope to be removed")
+	}
+	if b, ok := c.cachedBucketObject("opened"); !ok || b != opened {
+		t.Fatal("expected the opened bucket to be cached")
+	}
+	opened.scopes["scope"].removeCollection("collection")
+	if collections := opened.scopes["scope"].collections; len(collections) != 1 || collections["other"] == nil {
+		t.Fatalf("expected only the dropped collection to be removed, got %v", collections)
+	}
+
+	c.removeBucketObject("failed")
+	c.removeBucketObject("opened")
+	if _, ok := c.Buckets["opened"]; ok {
+		t.Fatal("expected the bucket to be removed")
+	}
+	if _, ok := c.bucketFailures["failed"]; ok {
+		t.Fatal("expected the bucket failure to be removed")
+	}
+}
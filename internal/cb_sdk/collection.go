@@ -1,9 +1,101 @@
 package cb_sdk
 
 import (
+	"fmt"
 	"github.com/couchbase/gocb/v2"
+	"strings"
+	"sync"
 )
 
 type CollectionObject struct {
-	Collection *gocb.Collection `json:"-"`
+	Collection     *gocb.Collection              `json:"-"`
+	mutationTokens map[uint64]gocb.MutationToken `json:"-"`
+	lock           sync.Mutex                    `json:"-"`
+}
+
+// RecordMutation keeps the latest mutation token of every vBucket mutated through the collection object. These
+// are used for at_plus scan consistency of queries.
+func (c *CollectionObject) RecordMutation(result *gocb.MutationResult) {
+	if result == nil || result.MutationToken() == nil {
+		return
+	}
+	token := *result.MutationToken()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.mutationTokens == nil {
+		c.mutationTokens = make(map[uint64]gocb.MutationToken)
+	}
+	if last, ok := c.mutationTokens[token.PartitionID()]; !ok || token.SequenceNumber() > last.SequenceNumber() {
+		c.mutationTokens[token.PartitionID()] = token
+	}
+}
+
+// MutationState returns the state made up of the latest mutation token of every vBucket mutated through the
+// collection object, or nil if nothing is mutated yet.
+func (c *CollectionObject) MutationState() *gocb.MutationState {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.mutationTokens) == 0 {
+		return nil
+	}
+	state := gocb.NewMutationState()
+	for _, token := range c.mutationTokens {
+		state.Add(token)
+	}
+	return state
+}
+
+// KeyLocation returns the vBucket of a document and the node hosting its active copy as per the cluster map currently
+// used by the SDK. The node is returned as host:port of its key value service, and is empty if the vBucket has no
+// active copy, as during a failover, or if the cluster map changed while locating the node.
+func (c *CollectionObject) KeyLocation(docId string) (uint16, string, error) {
+	if c.Collection == nil {
+		return 0, "", fmt.Errorf("collection is not opened")
+	}
+	agent, err := c.Collection.Bucket().Internal().IORouter()
+	if err != nil {
+		return 0, "", err
+	}
+	snapshot, err := agent.ConfigSnapshot()
+	if err != nil {
+		return 0, "", err
+	}
+	vBucket, err := snapshot.KeyToVbucket([]byte(docId))
+	if err != nil {
+		return 0, "", err
+	}
+	serverIndex, err := snapshot.VbucketToServer(vBucket, 0)
+	if err != nil {
+		return vBucket, "", nil
+	}
+	// the endpoints are not part of the snapshot and are only used if the cluster map did not change while reading
+	// them, as the server index may refer to another node in a different revision during a rebalance or failover.
+	endpoints := agent.MemdEps()
+	current, err := agent.ConfigSnapshot()
+	if err != nil || current.RevID() != snapshot.RevID() {
+		return vBucket, "", nil
+	}
+	if numServers, _ := snapshot.NumServers(); numServers != len(endpoints) || serverIndex < 0 ||
+		serverIndex >= len(endpoints) {
+		return vBucket, "", nil
+	}
+	node := strings.TrimPrefix(strings.TrimPrefix(endpoints[serverIndex], "couchbases://"), "couchbase://")
+	return vBucket, node, nil
+}
+
+// NumReplicas returns the number of replicas of the bucket of the collection as per the cluster map currently used by
+// the SDK.
+func (c *CollectionObject) NumReplicas() (int, error) {
+	if c.Collection == nil {
+		return 0, fmt.Errorf("collection is not opened")
+	}
+	agent, err := c.Collection.Bucket().Internal().IORouter()
+	if err != nil {
+		return 0, err
+	}
+	snapshot, err := agent.ConfigSnapshot()
+	if err != nil {
+		return 0, err
+	}
+	return snapshot.NumReplicas()
 }
@@ -0,0 +1,106 @@
+package cb_sdk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/couchbase/gocb/v2"
+	"sync"
+)
+
+// pooledCluster is a cluster connection shared by the ConnectionManager of every request using the same cluster
+// config.
+type pooledCluster struct {
+	cluster    *gocb.Cluster
+	references int
+	// bucketLock serialises opening buckets on the shared cluster.
+	bucketLock sync.Mutex
+	// evicted is set once the connection is evicted from the pool, so that every request using it reconnects.
+	evicted bool
+}
+
+// ConnectionPool is the process wide pool of cluster connections. A connection is opened by the first request
+// using a cluster config, and closed when the last request using it releases it.
+type ConnectionPool struct {
+	clusters map[string]*pooledCluster
+	lock     sync.Mutex
+}
+
+var connectionPool = &ConnectionPool{clusters: make(map[string]*pooledCluster)}
+
+// clusterPoolKey returns the key of a cluster config in the pool. Connections are shared only between requests
+// using the same connection string, credentials and cluster options.
+func clusterPoolKey(clusterConfig *ClusterConfig) (string, error) {
+	b, err := json.Marshal(clusterConfig)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// acquire returns the cluster connection for the cluster config, connecting to the cluster if no request is using
+// it, and adds a reference to it.
+func (p *ConnectionPool) acquire(clusterConfig *ClusterConfig) (*pooledCluster, string, error) {
+	key, err := clusterPoolKey(clusterConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	if pc, ok := p.clusters[key]; ok {
+		pc.references++
+		return pc, key, nil
+	}
+	clusterOptions, err := clusterConfig.clusterOptions()
+	if err != nil {
+		return nil, "", err
+	}
+	cluster, err := gocb.Connect(clusterConfig.ConnectionString, clusterOptions)
+	if err != nil {
+		return nil, "", err
+	}
+	pc := &pooledCluster{cluster: cluster, references: 1}
+	p.clusters[key] = pc
+	return pc, key, nil
+}
+
+// release removes a reference to a cluster connection and closes it if no request is using it anymore.
+func (p *ConnectionPool) release(key string, pc *pooledCluster) {
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	pc.references--
+	if pc.references > 0 {
+		return
+	}
+	_ = pc.cluster.Close(nil)
+	if p.clusters[key] == pc {
+		delete(p.clusters, key)
+	}
+}
+
+// evict removes a cluster connection from the pool and marks it evicted, so that every request using it, and the
+// next request using its cluster config, reconnects. The connection is closed once released by every request using
+// it.
+func (p *ConnectionPool) evict(key string, pc *pooledCluster) {
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	pc.evicted = true
+	if p.clusters[key] == pc {
+		delete(p.clusters, key)
+	}
+}
+
+// isEvicted returns true if a cluster connection was evicted from the pool.
+func (p *ConnectionPool) isEvicted(pc *pooledCluster) bool {
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	return pc.evicted
+}
+
+// references returns the number of requests using a cluster connection.
+func (p *ConnectionPool) references(pc *pooledCluster) int {
+	defer p.lock.Unlock()
+	p.lock.Lock()
+	return pc.references
+}
@@ -0,0 +1,55 @@
+package cb_sdk
+
+import (
+	"testing"
+)
+
+func TestConnectionPool(t *testing.T) {
+	pool := &ConnectionPool{clusters: make(map[string]*pooledCluster)}
+	config := func(password string) *ClusterConfig {
+		return &ClusterConfig{ConnectionString: "couchbase://127.0.0.1?kv_pool_size=1", Username: "Administrator",
+			Password: password}
+	}
+
+	first, key, err := pool.acquire(config("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, secondKey, err := pool.acquire(config("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second || key != secondKey || pool.references(first) != 2 {
+		t.Fatal("expected requests with the same cluster config to share the connection")
+	}
+	other, otherKey, err := pool.acquire(config("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == first || otherKey == key {
+		t.Fatal("expected requests with different credentials not to share the connection")
+	}
+
+	pool.release(key, first)
+	if _, ok := pool.clusters[key]; !ok {
+		t.Fatal("expected the connection to stay open while used by a request")
+	}
+	pool.release(key, first)
+	if _, ok := pool.clusters[key]; ok {
+		t.Fatal("expected the connection to be closed after the last release")
+	}
+
+	pool.evict(otherKey, other)
+	reconnected, _, err := pool.acquire(config("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reconnected == other || !pool.isEvicted(other) || pool.isEvicted(reconnected) {
+		t.Fatal("expected an evicted connection to be marked evicted and reopened")
+	}
+	pool.release(otherKey, other)
+	pool.release(otherKey, reconnected)
+	if len(pool.clusters) != 0 {
+		t.Fatalf("expected an empty pool, got %d connections", len(pool.clusters))
+	}
+}
@@ -0,0 +1,107 @@
+package cb_sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"time"
+)
+
+// ReplicaVerification is the comparison of the replica copies of a document with its active copy. A replica copy
+// matches if it has the CAS and content of the active copy. StaleReplicas are the CAS of the replica copies with
+// another CAS, and ContentMismatch counts the replica copies with the CAS but not the content of the active copy.
+type ReplicaVerification struct {
+	ActiveCas        uint64   `json:"activeCas" doc:"true"`
+	ExpectedReplicas int      `json:"expectedReplicas" doc:"true"`
+	Replicas         int      `json:"replicas" doc:"true"`
+	MatchingReplicas int      `json:"matchingReplicas" doc:"true"`
+	StaleReplicas    []uint64 `json:"staleReplicas,omitempty" doc:"true"`
+	ContentMismatch  int      `json:"contentMismatch,omitempty" doc:"true"`
+}
+
+// UnderReplicated returns true if fewer replica copies than expected match the active copy.
+func (v *ReplicaVerification) UnderReplicated() bool {
+	return v.MatchingReplicas < v.ExpectedReplicas
+}
+
+type documentCopy struct {
+	cas     uint64
+	content json.RawMessage
+	decoded bool
+}
+
+func (d *documentCopy) matches(active *documentCopy) (bool, bool) {
+	if d.cas != active.cas {
+		return false, false
+	}
+	if d.decoded && active.decoded && !bytes.Equal(d.content, active.content) {
+		return false, true
+	}
+	return true, false
+}
+
+// compare compares the replica copies with the active copy.
+func (v *ReplicaVerification) compare(active *documentCopy, replicas []documentCopy) {
+	v.ActiveCas = active.cas
+	v.Replicas = len(replicas)
+	for i := range replicas {
+		matches, contentMismatch := replicas[i].matches(active)
+		switch {
+		case matches:
+			v.MatchingReplicas++
+		case contentMismatch:
+			v.ContentMismatch++
+		default:
+			v.StaleReplicas = append(v.StaleReplicas, replicas[i].cas)
+		}
+	}
+}
+
+// VerifyReplicas reads the active and replica copies of a document using GetAllReplicas and compares the replica copies
+// with the active copy. It returns task_errors.ErrUnderReplicated if fewer than expectedReplicas replica copies match
+// the active copy. The active copy is read with Get if it was not received with the replica copies.
+func VerifyReplicas(collection *gocb.Collection, docId string, expectedReplicas int,
+	timeout time.Duration) (ReplicaVerification, error) {
+	verification := ReplicaVerification{ExpectedReplicas: expectedReplicas}
+
+	replicasResult, err := collection.GetAllReplicas(docId, &gocb.GetAllReplicaOptions{
+		Timeout: timeout,
+	})
+	if err != nil {
+		return verification, err
+	}
+	var active *documentCopy
+	var replicas []documentCopy
+	for replicaResult := replicasResult.Next(); replicaResult != nil; replicaResult = replicasResult.Next() {
+		c := documentCopy{cas: uint64(replicaResult.Cas())}
+		c.decoded = replicaResult.Content(&c.content) == nil
+		if replicaResult.IsReplica() {
+			replicas = append(replicas, c)
+		} else {
+			active = &c
+		}
+	}
+	if err := replicasResult.Close(); err != nil {
+		return verification, err
+	}
+
+	if active == nil {
+		getResult, err := collection.Get(docId, &gocb.GetOptions{
+			Timeout: timeout,
+		})
+		if err != nil {
+			return verification, err
+		}
+		active = &documentCopy{cas: uint64(getResult.Cas())}
+		active.decoded = getResult.Content(&active.content) == nil
+	}
+
+	verification.compare(active, replicas)
+	if verification.UnderReplicated() {
+		return verification, fmt.Errorf("%w : %d of %d replicas match the active copy", task_errors.ErrUnderReplicated,
+			verification.MatchingReplicas, verification.ExpectedReplicas)
+	}
+	return verification, nil
+}
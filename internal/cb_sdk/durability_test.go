@@ -0,0 +1,32 @@
+package cb_sdk
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestReplicaVerification(t *testing.T) {
+	active := &documentCopy{cas: 10, content: json.RawMessage(`{"a":1}`), decoded: true}
+	replicas := []documentCopy{
+		{cas: 10, content: json.RawMessage(`{"a":1}`), decoded: true},
+		{cas: 10, content: json.RawMessage(`{"a":2}`), decoded: true},
+		{cas: 7, content: json.RawMessage(`{"a":0}`), decoded: true},
+		{cas: 10},
+	}
+
+	v := ReplicaVerification{ExpectedReplicas: 3}
+	v.compare(active, replicas)
+	if v.ActiveCas != 10 || v.Replicas != 4 || v.MatchingReplicas != 2 || v.ContentMismatch != 1 ||
+		len(v.StaleReplicas) != 1 || v.StaleReplicas[0] != 7 {
+		t.Fatalf("unexpected verification %+v", v)
+	}
+	if !v.UnderReplicated() {
+		t.Fatal("expected the document to be under replicated")
+	}
+
+	v = ReplicaVerification{ExpectedReplicas: 2}
+	v.compare(active, replicas)
+	if v.UnderReplicated() {
+		t.Fatal("expected the document to be fully replicated")
+	}
+}
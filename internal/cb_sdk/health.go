@@ -0,0 +1,180 @@
+package cb_sdk
+
+import (
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"log"
+	"strings"
+	"time"
+)
+
+// HealthCheckTimeout is the timeout of every ping of a health check in seconds.
+const HealthCheckTimeout = 5
+
+// MaxHealthCheckFailures is the number of consecutive failed health checks after which a connection is evicted and
+// reopened by the next task using it.
+const MaxHealthCheckFailures = 3
+
+// ConnectionHealth is the result of the health checks of a connection to a cluster or a bucket.
+type ConnectionHealth struct {
+	Healthy     bool      `json:"healthy"`
+	Failures    int       `json:"failures"`
+	Error       string    `json:"error,omitempty"`
+	LastChecked time.Time `json:"lastChecked"`
+}
+
+// record updates the health with the result of a health check and returns true if the connection should be evicted.
+func (h *ConnectionHealth) record(err error) bool {
+	h.LastChecked = time.Now()
+	if err == nil {
+		h.Healthy, h.Failures, h.Error = true, 0, ""
+		return false
+	}
+	h.Healthy = false
+	h.Failures++
+	h.Error = err.Error()
+	return h.Failures >= MaxHealthCheckFailures
+}
+
+// BucketState is the state of a bucket connection.
+type BucketState struct {
+	Bucket string `json:"bucket"`
+	ConnectionHealth
+}
+
+// ClusterState is the state of a cluster connection and the buckets opened on it. SharedBy is the number of
+// requests using the connection.
+type ClusterState struct {
+	Cluster  string `json:"cluster"`
+	SharedBy int    `json:"sharedBy"`
+	ConnectionHealth
+	Buckets []BucketState `json:"buckets,omitempty"`
+}
+
+// pingError returns an error if no endpoint of the ping result is reachable.
+func pingError(result *gocb.PingResult, err error) error {
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for _, reports := range result.Services {
+		for _, report := range reports {
+			if report.State == gocb.PingStateOk {
+				return nil
+			}
+			if report.Error != "" {
+				errs = append(errs, fmt.Sprintf("%s : %s", report.Remote, report.Error))
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("no endpoint reachable")
+	}
+	return fmt.Errorf("%s", strings.Join(errs, " | "))
+}
+
+// checkHealth pings the management service of the cluster and the key value service of every opened bucket.
+func (c *ClusterObject) checkHealth() (map[string]error, error) {
+	clusterErr := pingError(c.Cluster.Ping(&gocb.PingOptions{
+		ServiceTypes: []gocb.ServiceType{gocb.ServiceTypeManagement},
+		Timeout:      HealthCheckTimeout * time.Second,
+	}))
+	bucketErrs := make(map[string]error)
+	for name, b := range c.Buckets {
+		bucketErrs[name] = pingError(b.bucket.Ping(&gocb.PingOptions{
+			ServiceTypes: []gocb.ServiceType{gocb.ServiceTypeKeyValue},
+			Timeout:      HealthCheckTimeout * time.Second,
+		}))
+	}
+	return bucketErrs, clusterErr
+}
+
+// CheckHealth runs a health check of every cluster and bucket connection. A connection failing
+// MaxHealthCheckFailures consecutive checks is evicted, so that a cluster rebuilt at the same address is reconnected
+// by the next task. A shared cluster connection is evicted for every request using it, each reconnecting on its next
+// task. An evicted cluster connection is closed once released by every request using it, even if tasks
+// are still running on it, failing their remaining operations. A change of credentials does not wait for the health
+// checks, as the next task using the new credentials reconnects. It returns the state of the connections after the
+// check.
+func (cm *ConnectionManager) CheckHealth() []ClusterState {
+	cm.lock.Lock()
+	clusters := make(map[string]*ClusterObject, len(cm.Clusters))
+	buckets := make(map[string]map[string]*BucketObject, len(cm.Clusters))
+	for identifier, c := range cm.Clusters {
+		clusters[identifier] = c
+		c.lock.Lock()
+		buckets[identifier] = make(map[string]*BucketObject, len(c.Buckets))
+		for name, b := range c.Buckets {
+			buckets[identifier][name] = b
+		}
+		c.lock.Unlock()
+	}
+	cm.lock.Unlock()
+
+	// pings are sent without holding the lock as they can take up to HealthCheckTimeout.
+	type healthCheck struct {
+		clusterErr error
+		bucketErrs map[string]error
+	}
+	checks := make(map[string]healthCheck, len(clusters))
+	for identifier, c := range clusters {
+		snapshot := &ClusterObject{Cluster: c.Cluster, Buckets: buckets[identifier]}
+		bucketErrs, clusterErr := snapshot.checkHealth()
+		checks[identifier] = healthCheck{clusterErr: clusterErr, bucketErrs: bucketErrs}
+	}
+
+	defer cm.lock.Unlock()
+	cm.lock.Lock()
+	for identifier, check := range checks {
+		c, ok := cm.Clusters[identifier]
+		if !ok || c != clusters[identifier] {
+			continue
+		}
+		if c.Health.record(check.clusterErr) {
+			log.Println("evicting unhealthy cluster connection", identifier, c.Health.Error)
+			if c.pooled != nil {
+				connectionPool.evict(c.poolKey, c.pooled)
+			}
+			Close(c)
+			delete(cm.Clusters, identifier)
+			continue
+		}
+		c.lock.Lock()
+		for name, err := range check.bucketErrs {
+			b, ok := c.Buckets[name]
+			if !ok || b != buckets[identifier][name] {
+				continue
+			}
+			if b.Health.record(err) {
+				log.Println("evicting unhealthy bucket connection", identifier, name, b.Health.Error)
+				delete(c.Buckets, name)
+			}
+		}
+		c.lock.Unlock()
+	}
+	return cm.states()
+}
+
+// ConnectionStates returns the state of every cluster and bucket connection as per the last health check.
+func (cm *ConnectionManager) ConnectionStates() []ClusterState {
+	defer cm.lock.RUnlock()
+	cm.lock.RLock()
+	return cm.states()
+}
+
+func (cm *ConnectionManager) states() []ClusterState {
+	states := make([]ClusterState, 0, len(cm.Clusters))
+	for identifier, c := range cm.Clusters {
+		state := ClusterState{Cluster: identifier, SharedBy: 1, ConnectionHealth: c.Health}
+		if c.pooled != nil {
+			state.SharedBy = connectionPool.references(c.pooled)
+		}
+		c.lock.Lock()
+		for name, b := range c.Buckets {
+			state.Buckets = append(state.Buckets, BucketState{Bucket: name, ConnectionHealth: b.Health})
+		}
+		c.lock.Unlock()
+		states = append(states, state)
+	}
+	return states
+}
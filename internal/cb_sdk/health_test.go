@@ -0,0 +1,42 @@
+package cb_sdk
+
+import (
+	"errors"
+	"github.com/couchbase/gocb/v2"
+	"testing"
+)
+
+func TestConnectionHealth(t *testing.T) {
+	h := &ConnectionHealth{Healthy: true}
+	for i := 1; i < MaxHealthCheckFailures; i++ {
+		if h.record(errors.New("unreachable")) {
+			t.Fatalf("unexpected eviction after %d failures", i)
+		}
+	}
+	if h.Healthy || h.Failures != MaxHealthCheckFailures-1 {
+		t.Fatalf("unexpected health %+v", h)
+	}
+	if h.record(nil) || !h.Healthy || h.Failures != 0 || h.Error != "" {
+		t.Fatalf("expected a successful check to reset the failures, got %+v", h)
+	}
+	for i := 1; i < MaxHealthCheckFailures; i++ {
+		h.record(errors.New("unreachable"))
+	}
+	if !h.record(errors.New("unreachable")) {
+		t.Fatal("expected eviction")
+	}
+
+	down := gocb.EndpointPingReport{Remote: "node1:8091", State: gocb.PingStateError, Error: "refused"}
+	up := gocb.EndpointPingReport{Remote: "node2:8091", State: gocb.PingStateOk}
+	if err := pingError(&gocb.PingResult{Services: map[gocb.ServiceType][]gocb.EndpointPingReport{
+		gocb.ServiceTypeManagement: {down, up}}}, nil); err != nil {
+		t.Fatalf("expected a reachable endpoint to be healthy, got %v", err)
+	}
+	if err := pingError(&gocb.PingResult{Services: map[gocb.ServiceType][]gocb.EndpointPingReport{
+		gocb.ServiceTypeManagement: {down}}}, nil); err == nil {
+		t.Fatal("expected an error when no endpoint is reachable")
+	}
+	if err := pingError(&gocb.PingResult{}, nil); err == nil {
+		t.Fatal("expected an error without endpoints")
+	}
+}
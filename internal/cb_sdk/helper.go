@@ -1,8 +1,11 @@
 package cb_sdk
 
 import (
+	"fmt"
 	"github.com/couchbase/gocb/v2"
 	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"strconv"
+	"strings"
 )
 
 const (
@@ -14,6 +17,24 @@ const (
 	DefaultBucket                             string = "default"
 	MaxQueryRuntime                           int    = 86400
 	DefaultQueryRunTime                       int    = 100
+	MaxLockTime                               int    = 30
+	ReadModeGet                               string = "get"
+	ReadModeGetAndTouch                       string = "getAndTouch"
+	ReadModeExists                            string = "exists"
+	ReadModeGetAnyReplica                     string = "getAnyReplica"
+	ReadModeGetAllReplicas                    string = "getAllReplicas"
+	ScanTypeRange                             string = "range"
+	ScanTypeSampling                          string = "sampling"
+	DefaultSearchIndexTimeout                 int    = 600
+	DefaultAnalyticsIngestionTimeout          int    = 600
+	DefaultAnalyticsDataverse                 string = "sirius"
+	ScanConsistencyNotBounded                 string = "not_bounded"
+	ScanConsistencyRequestPlus                string = "request_plus"
+	ScanConsistencyAtPlus                     string = "at_plus"
+	DefaultWatchIndexTimeout                  int    = 120
+	DefaultBucketRamQuotaMB                   uint64 = 256
+	DefaultManagementConcurrency              int    = 10
+	MaxNamesInPattern                         int    = 10000
 )
 
 // GetDurability returns gocb.DurabilityLevel required for Doc loading operation
@@ -51,6 +72,8 @@ type InsertOptions struct {
 	ReplicateTo uint   `json:"replicateTo,omitempty" doc:"true"`
 	Durability  string `json:"durability,omitempty" doc:"true"`
 	Timeout     int    `json:"timeout,omitempty" doc:"true"`
+	// PreserveExpiry keeps the expiry of existing documents when upserting them.
+	PreserveExpiry bool `json:"preserveExpiry,omitempty" doc:"true"`
 }
 
 // ConfigInsertOptions configures and validate the InsertOptions
@@ -61,6 +84,9 @@ func ConfigInsertOptions(i *InsertOptions) error {
 	if i.Timeout == 0 {
 		i.Timeout = 10
 	}
+	if i.PreserveExpiry && i.Expiry > 0 {
+		return fmt.Errorf("%w : preserveExpiry cannot be used with expiry", task_errors.ErrParsingInsertOptions)
+	}
 	return nil
 }
 
@@ -116,11 +142,226 @@ func ConfigReplaceOptions(r *ReplaceOptions) error {
 	return nil
 }
 
+// CounterOptions are used when performing increment or decrement operation on CB server.
+type CounterOptions struct {
+	Initial     int64  `json:"initial,omitempty" doc:"true"`
+	Delta       uint64 `json:"delta,omitempty" doc:"true"`
+	Expiry      int64  `json:"expiry,omitempty" doc:"true"`
+	PersistTo   uint   `json:"persistTo,omitempty" doc:"true"`
+	ReplicateTo uint   `json:"replicateTo,omitempty" doc:"true"`
+	Durability  string `json:"durability,omitempty" doc:"true"`
+	Timeout     int    `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigCounterOptions configures and validate the CounterOptions
+func ConfigCounterOptions(c *CounterOptions) error {
+	if c == nil {
+		return task_errors.ErrParsingCounterOptions
+	}
+	if c.Delta == 0 {
+		c.Delta = 1
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 10
+	}
+	return nil
+}
+
+// BinaryOptions are used when performing append or prepend operation on CB server.
+type BinaryOptions struct {
+	Cas         uint64 `json:"cas,omitempty" doc:"true"`
+	PersistTo   uint   `json:"persistTo,omitempty" doc:"true"`
+	ReplicateTo uint   `json:"replicateTo,omitempty" doc:"true"`
+	Durability  string `json:"durability,omitempty" doc:"true"`
+	Timeout     int    `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigBinaryOptions configures and validate the BinaryOptions
+func ConfigBinaryOptions(b *BinaryOptions) error {
+	if b == nil {
+		return task_errors.ErrParsingBinaryOptions
+	}
+	if b.Timeout == 0 {
+		b.Timeout = 10
+	}
+	return nil
+}
+
+// LockOptions are used when performing get and lock operation on CB server.
+type LockOptions struct {
+	LockTime           int  `json:"lockTime,omitempty" doc:"true"`
+	HoldTime           int  `json:"holdTime,omitempty" doc:"true"`
+	ContentionAttempts int  `json:"contentionAttempts,omitempty" doc:"true"`
+	SkipUnlock         bool `json:"skipUnlock,omitempty" doc:"true"`
+	Timeout            int  `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigLockOptions configures and validate the LockOptions
+func ConfigLockOptions(l *LockOptions) error {
+	if l == nil {
+		return task_errors.ErrParsingLockOptions
+	}
+	if l.LockTime == 0 {
+		l.LockTime = 15
+	}
+	if l.LockTime < 0 || l.LockTime > MaxLockTime {
+		return task_errors.ErrInvalidLockTime
+	}
+	if l.HoldTime < 0 || l.ContentionAttempts < 0 {
+		return task_errors.ErrParsingLockOptions
+	}
+	if l.Timeout == 0 {
+		l.Timeout = 10
+	}
+	return nil
+}
+
+// UnlockOptions are used when performing unlock operation on CB server. Cas unlocks a single key, CasByKey
+// holds the CAS of every key to unlock, as reported by the lock tasks.
+type UnlockOptions struct {
+	Cas      uint64            `json:"cas,omitempty" doc:"true"`
+	CasByKey map[string]uint64 `json:"casByKey,omitempty" doc:"true"`
+	Timeout  int               `json:"timeout,omitempty" doc:"true"`
+}
+
+// UnlockCas returns the CAS with which a key has to be unlocked.
+func (u *UnlockOptions) UnlockCas(key string) uint64 {
+	if cas, ok := u.CasByKey[key]; ok {
+		return cas
+	}
+	return u.Cas
+}
+
+// ConfigUnlockOptions configures and validate the UnlockOptions
+func ConfigUnlockOptions(u *UnlockOptions) error {
+	if u == nil {
+		return task_errors.ErrParsingUnlockOptions
+	}
+	if u.Timeout == 0 {
+		u.Timeout = 10
+	}
+	return nil
+}
+
+// ReadOptions are used to select how documents are read from CB server.
+type ReadOptions struct {
+	ReadMode string   `json:"readMode,omitempty" doc:"true"`
+	Expiry   int64    `json:"expiry,omitempty" doc:"true"`
+	Project  []string `json:"project,omitempty" doc:"true"`
+	Timeout  int      `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigReadOptions configures and validate the ReadOptions
+func ConfigReadOptions(r *ReadOptions) error {
+	if r == nil {
+		return task_errors.ErrParsingReadOptions
+	}
+	switch r.ReadMode {
+	case "":
+		r.ReadMode = ReadModeGet
+	case ReadModeGet, ReadModeGetAndTouch, ReadModeExists, ReadModeGetAnyReplica, ReadModeGetAllReplicas:
+	default:
+		return task_errors.ErrInvalidReadMode
+	}
+	if r.Expiry < 0 {
+		return task_errors.ErrParsingReadOptions
+	}
+	if len(r.Project) > 0 && r.ReadMode != ReadModeGet {
+		return task_errors.ErrProjectionNotSupported
+	}
+	if r.Timeout == 0 {
+		r.Timeout = 10
+	}
+	return nil
+}
+
+// ScanOptions are used when performing range or sampling scan on CB server.
+type ScanOptions struct {
+	ScanType       string `json:"scanType,omitempty" doc:"true"`
+	IdsOnly        bool   `json:"idsOnly,omitempty" doc:"true"`
+	Limit          uint64 `json:"limit,omitempty" doc:"true"`
+	Seed           uint64 `json:"seed,omitempty" doc:"true"`
+	BatchByteLimit uint32 `json:"batchByteLimit,omitempty" doc:"true"`
+	BatchItemLimit uint32 `json:"batchItemLimit,omitempty" doc:"true"`
+	Timeout        int    `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigScanOptions configures and validate the ScanOptions
+func ConfigScanOptions(s *ScanOptions) error {
+	if s == nil {
+		return task_errors.ErrParsingScanOptions
+	}
+	switch s.ScanType {
+	case "":
+		s.ScanType = ScanTypeRange
+	case ScanTypeRange, ScanTypeSampling:
+	default:
+		return task_errors.ErrInvalidScanType
+	}
+	if s.ScanType == ScanTypeSampling && s.Limit == 0 {
+		s.Limit = 100
+	}
+	if s.Timeout == 0 {
+		s.Timeout = 60
+	}
+	return nil
+}
+
+// TransactionOptions are used when performing multi document transactions on CB server.
+type TransactionOptions struct {
+	DocsPerTransaction int     `json:"docsPerTransaction,omitempty" doc:"true"`
+	RemoveRatio        float64 `json:"removeRatio,omitempty" doc:"true"`
+	RollbackRatio      float64 `json:"rollbackRatio,omitempty" doc:"true"`
+	Durability         string  `json:"durability,omitempty" doc:"true"`
+	Timeout            int     `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigTransactionOptions configures and validate the TransactionOptions
+func ConfigTransactionOptions(t *TransactionOptions) error {
+	if t == nil {
+		return task_errors.ErrParsingTransactionOptions
+	}
+	if t.DocsPerTransaction <= 0 {
+		t.DocsPerTransaction = 5
+	}
+	if t.RemoveRatio < 0 || t.RemoveRatio > 1 || t.RollbackRatio < 0 || t.RollbackRatio > 1 {
+		return task_errors.ErrInvalidRatio
+	}
+	if t.Timeout == 0 {
+		t.Timeout = 15
+	}
+	return nil
+}
+
 type QueryOperationConfig struct {
-	Template         string `json:"template,omitempty" doc:"true"`
-	Duration         int    `json:"duration,omitempty" doc:"true"`
-	BuildIndex       bool   `json:"buildIndex" doc:"true"`
-	BuildIndexViaSDK bool   `json:"buildIndexViaSDK" doc:"true"`
+	Template         string            `json:"template,omitempty" doc:"true"`
+	Duration         int               `json:"duration,omitempty" doc:"true"`
+	BuildIndex       bool              `json:"buildIndex" doc:"true"`
+	BuildIndexViaSDK bool              `json:"buildIndexViaSDK" doc:"true"`
+	Queries          []QueryDefinition `json:"queries,omitempty" doc:"true"`
+	ValidateResults  bool              `json:"validateResults,omitempty" doc:"true"`
+}
+
+// QueryParameter is a parameter of a user supplied query. Parameters with a name are passed as named
+// parameters and the rest as positional parameters. The value is taken from Path of the document of a random
+// key loaded by Sirius, or is Value if no path is given.
+type QueryParameter struct {
+	Name  string      `json:"name,omitempty" doc:"true"`
+	Path  string      `json:"path,omitempty" doc:"true"`
+	Value interface{} `json:"value,omitempty" doc:"true"`
+}
+
+// QueryDefinition is a user supplied N1QL query run instead of the template queries. Queries are picked as per
+// their weight. at_plus scan consistency waits for the mutations done by Sirius on the collection. Transactions do
+// not record mutation tokens, and a query asking for at_plus before any mutation is recorded runs not_bounded,
+// which is counted as atPlusFallbacks in the query result.
+type QueryDefinition struct {
+	Statement       string           `json:"statement" doc:"true"`
+	Parameters      []QueryParameter `json:"parameters,omitempty" doc:"true"`
+	Weight          int              `json:"weight,omitempty" doc:"true"`
+	ScanConsistency string           `json:"scanConsistency,omitempty" doc:"true"`
+	Prepared        bool             `json:"prepared,omitempty" doc:"true"`
+	Timeout         int              `json:"timeout,omitempty" doc:"true"`
 }
 
 func ConfigQueryOperationConfig(s *QueryOperationConfig) error {
@@ -131,9 +372,325 @@ func ConfigQueryOperationConfig(s *QueryOperationConfig) error {
 	if s.Duration == 0 || s.Duration > MaxQueryRuntime {
 		s.Duration = DefaultQueryRunTime
 	}
+
+	for i := range s.Queries {
+		if err := configQueryDefinition(&s.Queries[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configQueryDefinition configures and validate a QueryDefinition
+func configQueryDefinition(q *QueryDefinition) error {
+	if q.Statement == "" {
+		return task_errors.ErrEmptyQueryStatement
+	}
+	if q.Weight < 0 {
+		return task_errors.ErrInvalidQueryWeight
+	}
+	if q.Weight == 0 {
+		q.Weight = 1
+	}
+	switch q.ScanConsistency {
+	case "":
+		q.ScanConsistency = ScanConsistencyNotBounded
+	case ScanConsistencyNotBounded, ScanConsistencyRequestPlus, ScanConsistencyAtPlus:
+	default:
+		return task_errors.ErrInvalidScanConsistency
+	}
+	named := 0
+	for _, p := range q.Parameters {
+		if p.Name != "" {
+			named++
+		}
+	}
+	if named != 0 && named != len(q.Parameters) {
+		return task_errors.ErrMixedQueryParameters
+	}
+	return nil
+}
+
+// SearchOperationConfig is used to run full text search queries on CB server.
+type SearchOperationConfig struct {
+	Template     string `json:"template,omitempty" doc:"true"`
+	IndexName    string `json:"indexName,omitempty" doc:"true"`
+	Duration     int    `json:"duration,omitempty" doc:"true"`
+	IndexTimeout int    `json:"indexTimeout,omitempty" doc:"true"`
+	Limit        uint32 `json:"limit,omitempty" doc:"true"`
+	Timeout      int    `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigSearchOperationConfig configures and validate the SearchOperationConfig
+func ConfigSearchOperationConfig(s *SearchOperationConfig) error {
+	if s == nil {
+		return task_errors.ErrParsingSearchOperationConfig
+	}
+	if s.Duration == 0 || s.Duration > MaxQueryRuntime {
+		s.Duration = DefaultQueryRunTime
+	}
+	if s.IndexTimeout == 0 {
+		s.IndexTimeout = DefaultSearchIndexTimeout
+	}
+	if s.Limit == 0 {
+		s.Limit = 100
+	}
+	if s.Timeout == 0 {
+		s.Timeout = 10
+	}
 	return nil
 }
 
+// AnalyticsOperationConfig is used to run analytics queries on CB server. JoinScope and JoinCollection name
+// a second collection of the same bucket loaded by Sirius, which is joined with the collection of the task.
+type AnalyticsOperationConfig struct {
+	Template         string `json:"template,omitempty" doc:"true"`
+	Dataverse        string `json:"dataverse,omitempty" doc:"true"`
+	JoinScope        string `json:"joinScope,omitempty" doc:"true"`
+	JoinCollection   string `json:"joinCollection,omitempty" doc:"true"`
+	Duration         int    `json:"duration,omitempty" doc:"true"`
+	IngestionTimeout int    `json:"ingestionTimeout,omitempty" doc:"true"`
+	Timeout          int    `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigAnalyticsOperationConfig configures and validate the AnalyticsOperationConfig
+func ConfigAnalyticsOperationConfig(a *AnalyticsOperationConfig) error {
+	if a == nil {
+		return task_errors.ErrParsingAnalyticsOperationConfig
+	}
+	if a.Dataverse == "" {
+		a.Dataverse = DefaultAnalyticsDataverse
+	}
+	if a.JoinCollection != "" && a.JoinScope == "" {
+		a.JoinScope = DefaultScope
+	}
+	if a.Duration == 0 || a.Duration > MaxQueryRuntime {
+		a.Duration = DefaultQueryRunTime
+	}
+	if a.IngestionTimeout == 0 {
+		a.IngestionTimeout = DefaultAnalyticsIngestionTimeout
+	}
+	if a.Timeout == 0 {
+		a.Timeout = 75
+	}
+	return nil
+}
+
+// IndexDefinition defines a GSI index. An index without fields is a primary index. PartitionBy is the
+// partition key expression of a partitioned index, e.g. HASH(meta().id).
+type IndexDefinition struct {
+	Name         string   `json:"name" doc:"true"`
+	Fields       []string `json:"fields,omitempty" doc:"true"`
+	Where        string   `json:"where,omitempty" doc:"true"`
+	PartitionBy  string   `json:"partitionBy,omitempty" doc:"true"`
+	NumPartition int      `json:"numPartition,omitempty" doc:"true"`
+	NumReplica   int      `json:"numReplica,omitempty" doc:"true"`
+}
+
+// IndexOptions are used for managing GSI indexes on a collection. If no indexes are defined, the indexes of
+// the template are used while creating and all the indexes of the collection otherwise. NumReplica,
+// NumPartition and PartitionBy apply to the indexes which do not define them.
+type IndexOptions struct {
+	Template     string            `json:"template,omitempty" doc:"true"`
+	Indexes      []IndexDefinition `json:"indexes,omitempty" doc:"true"`
+	Deferred     bool              `json:"deferred,omitempty" doc:"true"`
+	NumReplica   int               `json:"numReplica,omitempty" doc:"true"`
+	NumPartition int               `json:"numPartition,omitempty" doc:"true"`
+	PartitionBy  string            `json:"partitionBy,omitempty" doc:"true"`
+	WatchPrimary bool              `json:"watchPrimary,omitempty" doc:"true"`
+	WatchTimeout int               `json:"watchTimeout,omitempty" doc:"true"`
+	Timeout      int               `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigIndexOptions configures and validate the IndexOptions
+func ConfigIndexOptions(i *IndexOptions) error {
+	if i == nil {
+		return task_errors.ErrParsingIndexOptions
+	}
+	if i.NumReplica < 0 || i.NumPartition < 0 {
+		return task_errors.ErrInvalidIndexOptions
+	}
+	for index := range i.Indexes {
+		d := &i.Indexes[index]
+		if d.NumReplica < 0 || d.NumPartition < 0 {
+			return task_errors.ErrInvalidIndexOptions
+		}
+		if d.Name == "" && len(d.Fields) > 0 {
+			return task_errors.ErrIndexNameMissing
+		}
+		if d.NumReplica == 0 {
+			d.NumReplica = i.NumReplica
+		}
+		if d.NumPartition == 0 {
+			d.NumPartition = i.NumPartition
+		}
+		if d.PartitionBy == "" {
+			d.PartitionBy = i.PartitionBy
+		}
+	}
+	if i.WatchTimeout == 0 {
+		i.WatchTimeout = DefaultWatchIndexTimeout
+	}
+	if i.Timeout == 0 {
+		i.Timeout = 75
+	}
+	return nil
+}
+
+// BucketOptions are used for creating a bucket. NumReplicas is used as is, i.e. a bucket is created without
+// replicas if it is not set. MaxTTL is in seconds.
+type BucketOptions struct {
+	BucketType             string `json:"bucketType,omitempty" doc:"true"`
+	RamQuotaMB             uint64 `json:"ramQuotaMB,omitempty" doc:"true"`
+	NumReplicas            uint32 `json:"numReplicas,omitempty" doc:"true"`
+	StorageBackend         string `json:"storageBackend,omitempty" doc:"true"`
+	EvictionPolicy         string `json:"evictionPolicy,omitempty" doc:"true"`
+	CompressionMode        string `json:"compressionMode,omitempty" doc:"true"`
+	ConflictResolutionType string `json:"conflictResolutionType,omitempty" doc:"true"`
+	MaxTTL                 int    `json:"maxTTL,omitempty" doc:"true"`
+	FlushEnabled           bool   `json:"flushEnabled,omitempty" doc:"true"`
+	ReplicaIndexDisabled   bool   `json:"replicaIndexDisabled,omitempty" doc:"true"`
+	Timeout                int    `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigBucketOptions configures and validate the BucketOptions
+func ConfigBucketOptions(b *BucketOptions) error {
+	if b == nil {
+		return task_errors.ErrParsingBucketOptions
+	}
+	switch gocb.BucketType(b.BucketType) {
+	case "":
+		b.BucketType = string(gocb.CouchbaseBucketType)
+	case gocb.CouchbaseBucketType, gocb.EphemeralBucketType, gocb.MemcachedBucketType:
+	default:
+		return task_errors.ErrInvalidBucketOptions
+	}
+	switch gocb.StorageBackend(b.StorageBackend) {
+	case "", gocb.StorageBackendCouchstore, gocb.StorageBackendMagma:
+	default:
+		return task_errors.ErrInvalidBucketOptions
+	}
+	switch gocb.EvictionPolicyType(b.EvictionPolicy) {
+	case "", gocb.EvictionPolicyTypeFull, gocb.EvictionPolicyTypeValueOnly, gocb.EvictionPolicyTypeNotRecentlyUsed,
+		gocb.EvictionPolicyTypeNoEviction:
+	default:
+		return task_errors.ErrInvalidBucketOptions
+	}
+	switch gocb.CompressionMode(b.CompressionMode) {
+	case "", gocb.CompressionModeOff, gocb.CompressionModePassive, gocb.CompressionModeActive:
+	default:
+		return task_errors.ErrInvalidBucketOptions
+	}
+	switch gocb.ConflictResolutionType(b.ConflictResolutionType) {
+	case "", gocb.ConflictResolutionTypeTimestamp, gocb.ConflictResolutionTypeSequenceNumber,
+		gocb.ConflictResolutionTypeCustom:
+	default:
+		return task_errors.ErrInvalidBucketOptions
+	}
+	if b.MaxTTL < 0 {
+		return task_errors.ErrInvalidBucketOptions
+	}
+	if b.RamQuotaMB == 0 {
+		b.RamQuotaMB = DefaultBucketRamQuotaMB
+	}
+	if b.Timeout == 0 {
+		b.Timeout = 75
+	}
+	return nil
+}
+
+// CollectionOptions are used for creating and dropping scopes and collections in bulk. Scopes and Collections
+// are name patterns where every [start-end] is expanded to the numbers in the range, e.g. coll_[0-999] names
+// 1000 collections. Collections are created or dropped in every scope named by Scopes. MaxTTL is in seconds.
+type CollectionOptions struct {
+	Scopes      string `json:"scopes,omitempty" doc:"true"`
+	Collections string `json:"collections,omitempty" doc:"true"`
+	MaxTTL      int    `json:"maxTTL,omitempty" doc:"true"`
+	Concurrency int    `json:"concurrency,omitempty" doc:"true"`
+	Timeout     int    `json:"timeout,omitempty" doc:"true"`
+}
+
+// ConfigCollectionOptions configures and validate the CollectionOptions
+func ConfigCollectionOptions(c *CollectionOptions) error {
+	if c == nil {
+		return task_errors.ErrParsingCollectionOptions
+	}
+	if c.Scopes == "" {
+		c.Scopes = DefaultScope
+	}
+	if _, err := ExpandNamePattern(c.Scopes); err != nil {
+		return err
+	}
+	if _, err := ExpandNamePattern(c.Collections); err != nil {
+		return err
+	}
+	if c.MaxTTL < 0 || c.Concurrency < 0 {
+		return task_errors.ErrParsingCollectionOptions
+	}
+	if c.Concurrency == 0 {
+		c.Concurrency = DefaultManagementConcurrency
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 75
+	}
+	return nil
+}
+
+// ExpandNamePattern returns the names described by a pattern. Every [start-end] in the pattern is replaced by
+// each number from start to end, padded with zeros if start is, e.g. scope_[00-02] expands to scope_00, scope_01
+// and scope_02. A pattern without a range is a single name and an empty pattern has no names.
+func ExpandNamePattern(pattern string) ([]string, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	open := strings.Index(pattern, "[")
+	if open == -1 {
+		if strings.Contains(pattern, "]") {
+			return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidNamePattern, pattern)
+		}
+		return []string{pattern}, nil
+	}
+	closing := strings.Index(pattern[open:], "]")
+	if closing == -1 {
+		return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidNamePattern, pattern)
+	}
+	closing += open
+
+	bounds := strings.SplitN(pattern[open+1:closing], "-", 2)
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidNamePattern, pattern)
+	}
+	start, err1 := strconv.Atoi(bounds[0])
+	end, err2 := strconv.Atoi(bounds[1])
+	if err1 != nil || err2 != nil || start < 0 || end < start {
+		return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidNamePattern, pattern)
+	}
+	width := 0
+	if len(bounds[0]) > 1 && bounds[0][0] == '0' {
+		width = len(bounds[0])
+	}
+
+	suffixes, err := ExpandNamePattern(pattern[closing+1:])
+	if err != nil {
+		return nil, err
+	}
+	if len(suffixes) == 0 {
+		suffixes = []string{""}
+	}
+	if (end-start+1)*len(suffixes) > MaxNamesInPattern {
+		return nil, fmt.Errorf("%w : %s names more than %d", task_errors.ErrInvalidNamePattern, pattern,
+			MaxNamesInPattern)
+	}
+
+	var names []string
+	for i := start; i <= end; i++ {
+		for _, suffix := range suffixes {
+			names = append(names, fmt.Sprintf("%s%0*d%s", pattern[:open], width, i, suffix))
+		}
+	}
+	return names, nil
+}
+
 type GetSpecOptions struct {
 	IsXattr bool `json:"isXattr,omitempty" doc:"true"`
 }
@@ -190,6 +747,21 @@ func ConfigReplaceSpecOptions(r *ReplaceSpecOptions) error {
 	return nil
 }
 
+const (
+	StoreSemanticsReplace string = "replace"
+	StoreSemanticsUpsert  string = "upsert"
+	StoreSemanticsInsert  string = "insert"
+)
+
+var storeSemantics = map[string]gocb.StoreSemantics{
+	StoreSemanticsReplace: gocb.StoreSemanticsReplace,
+	StoreSemanticsUpsert:  gocb.StoreSemanticsUpsert,
+	StoreSemanticsInsert:  gocb.StoreSemanticsInsert,
+}
+
+// MutateInOptions are used when performing sub document mutations. StoreSemantics is one of replace, upsert or
+// insert and takes precedence over the numeric StoreSemantic. PreserveExpiry takes precedence over Expiry, which
+// is ignored when both are set.
 type MutateInOptions struct {
 	Expiry         int    `json:"expiry,omitempty" doc:"true"`
 	Cas            uint64 `json:"cas,omitempty" doc:"true"`
@@ -197,6 +769,7 @@ type MutateInOptions struct {
 	ReplicateTo    uint   `json:"replicateTo,omitempty" doc:"true"`
 	Durability     string `json:"durability,omitempty" doc:"true"`
 	StoreSemantic  int    `json:"storeSemantic,omitempty" doc:"true"`
+	StoreSemantics string `json:"storeSemantics,omitempty" doc:"true"`
 	Timeout        int    `json:"timeout,omitempty" doc:"true"`
 	PreserveExpiry bool   `json:"preserveExpiry,omitempty" doc:"true"`
 }
@@ -205,6 +778,17 @@ func ConfigMutateInOptions(m *MutateInOptions) error {
 	if m == nil {
 		return task_errors.ErrParsingMutateInOptions
 	}
+	if m.StoreSemantics != "" {
+		semantic, ok := storeSemantics[strings.ToLower(m.StoreSemantics)]
+		if !ok {
+			return fmt.Errorf("%w : unknown storeSemantics %s", task_errors.ErrParsingMutateInOptions,
+				m.StoreSemantics)
+		}
+		m.StoreSemantic = int(semantic)
+	}
+	if m.PreserveExpiry {
+		m.Expiry = 0
+	}
 	return nil
 }
 
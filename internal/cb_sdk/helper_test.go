@@ -0,0 +1,73 @@
+package cb_sdk
+
+import (
+	"errors"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"reflect"
+	"testing"
+)
+
+func TestExpandNamePattern(t *testing.T) {
+	for pattern, expected := range map[string][]string{
+		"":                   nil,
+		"_default":           {"_default"},
+		"coll_[0-2]":         {"coll_0", "coll_1", "coll_2"},
+		"scope_[08-10]_x":    {"scope_08_x", "scope_09_x", "scope_10_x"},
+		"s[1-2].c[0-1]":      {"s1.c0", "s1.c1", "s2.c0", "s2.c1"},
+		"coll_[00-01]_[1-1]": {"coll_00_1", "coll_01_1"},
+	} {
+		names, err := ExpandNamePattern(pattern)
+		if err != nil {
+			t.Fatalf("%s : %v", pattern, err)
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Fatalf("%s : expected %v, got %v", pattern, expected, names)
+		}
+	}
+
+	for _, pattern := range []string{"coll_[1-0]", "coll_[a-b]", "coll_[1]", "coll_[1-2", "coll_1]", "c_[0-100000]"} {
+		if _, err := ExpandNamePattern(pattern); !errors.Is(err, task_errors.ErrInvalidNamePattern) {
+			t.Fatalf("%s : expected invalid pattern, got %v", pattern, err)
+		}
+	}
+}
+
+func TestConfigMutateInOptions(t *testing.T) {
+	for storeSemantics, expected := range map[string]gocb.StoreSemantics{
+		"":        gocb.StoreSemanticsReplace,
+		"replace": gocb.StoreSemanticsReplace,
+		"Upsert":  gocb.StoreSemanticsUpsert,
+		"insert":  gocb.StoreSemanticsInsert,
+	} {
+		m := &MutateInOptions{StoreSemantics: storeSemantics}
+		if err := ConfigMutateInOptions(m); err != nil {
+			t.Fatalf("%s : %v", storeSemantics, err)
+		}
+		if GetStoreSemantic(m.StoreSemantic) != expected {
+			t.Fatalf("%s : expected %v, got %v", storeSemantics, expected, GetStoreSemantic(m.StoreSemantic))
+		}
+	}
+
+	if err := ConfigMutateInOptions(&MutateInOptions{StoreSemantics: "merge"}); !errors.Is(err,
+		task_errors.ErrParsingMutateInOptions) {
+		t.Fatalf("expected invalid options, got %v", err)
+	}
+
+	// the expiry is ignored when preserveExpiry is set, whichever way the store semantics are given.
+	for _, m := range []*MutateInOptions{{StoreSemantic: 1, Expiry: 10, PreserveExpiry: true},
+		{StoreSemantics: "upsert", Expiry: 10, PreserveExpiry: true}} {
+		if err := ConfigMutateInOptions(m); err != nil || m.Expiry != 0 {
+			t.Fatalf("expected expiry to be ignored, got %+v %v", m, err)
+		}
+	}
+}
+
+func TestUnlockCas(t *testing.T) {
+	u := &UnlockOptions{Cas: 7, CasByKey: map[string]uint64{"a": 1, "b": 2}}
+	for key, expected := range map[string]uint64{"a": 1, "b": 2, "c": 7} {
+		if cas := u.UnlockCas(key); cas != expected {
+			t.Fatalf("%s : expected cas %d, got %d", key, expected, cas)
+		}
+	}
+}
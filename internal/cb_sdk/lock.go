@@ -0,0 +1,96 @@
+package cb_sdk
+
+import (
+	"errors"
+	"github.com/couchbase/gocb/v2"
+	"time"
+)
+
+// LockCycleResult holds the outcome of locking, contending on and unlocking a single document.
+type LockCycleResult struct {
+	Cas                  gocb.Cas
+	AcquireTime          time.Duration
+	HoldTime             time.Duration
+	UnlockTime           time.Duration
+	Unlocked             bool
+	ContentionAttempts   int64
+	DocumentLockedErrors int64
+	// ContentionError is set if the content of the locked document could not be decoded to contend on it.
+	ContentionError error
+}
+
+// lockedFailFastRetryStrategy fails an operation against a locked document without retrying it, while retrying
+// for every other reason as the wrapped strategy does.
+type lockedFailFastRetryStrategy struct {
+	gocb.RetryStrategy
+}
+
+func (s lockedFailFastRetryStrategy) RetryAfter(req gocb.RetryRequest, reason gocb.RetryReason) gocb.RetryAction {
+	if reason == gocb.KVLockedRetryReason {
+		return &gocb.NoRetryRetryAction{}
+	}
+	return s.RetryStrategy.RetryAfter(req, reason)
+}
+
+// LockDocument locks a document for LockOptions.LockTime, attempts ContentionAttempts mutations against
+// the locked document and waits for HoldTime milliseconds. It returns an error only if the lock could not be
+// acquired, the document being locked with LockCycleResult.Cas otherwise. The document is left locked and has
+// to be released with UnlockDocument, unless SkipUnlock is set.
+func LockDocument(collection *gocb.Collection, docId string, l *LockOptions) (LockCycleResult, error) {
+	var result LockCycleResult
+	timeout := time.Duration(l.Timeout) * time.Second
+
+	lockStart := time.Now()
+	getResult, err := collection.GetAndLock(docId, time.Duration(l.LockTime)*time.Second,
+		&gocb.GetAndLockOptions{
+			Transcoder: gocb.NewLegacyTranscoder(),
+			Timeout:    timeout,
+		})
+	result.AcquireTime = time.Since(lockStart)
+	if err != nil {
+		return result, err
+	}
+	lockedAt := time.Now()
+	result.Cas = getResult.Cas()
+
+	if l.ContentionAttempts > 0 {
+		var content interface{}
+		if err := getResult.Content(&content); err != nil {
+			result.ContentionError = err
+		}
+		for i := 0; i < l.ContentionAttempts && result.ContentionError == nil; i++ {
+			result.ContentionAttempts++
+			// the SDK retries a locked document until the timeout by default, which would replace the document
+			// once its lock expires and change the CAS held for unlocking it.
+			_, err := collection.Replace(docId, content, &gocb.ReplaceOptions{
+				Transcoder:    gocb.NewLegacyTranscoder(),
+				Timeout:       timeout,
+				RetryStrategy: lockedFailFastRetryStrategy{gocb.NewBestEffortRetryStrategy(nil)},
+			})
+			if errors.Is(err, gocb.ErrDocumentLocked) {
+				result.DocumentLockedErrors++
+			}
+		}
+	}
+
+	if l.HoldTime > 0 {
+		time.Sleep(time.Duration(l.HoldTime) * time.Millisecond)
+	}
+	result.HoldTime = time.Since(lockedAt)
+	return result, nil
+}
+
+// UnlockDocument unlocks a document locked by LockDocument with the CAS received while locking it and records the
+// time taken in the LockCycleResult.
+func UnlockDocument(collection *gocb.Collection, docId string, l *LockOptions, result *LockCycleResult) error {
+	unlockStart := time.Now()
+	err := collection.Unlock(docId, result.Cas, &gocb.UnlockOptions{
+		Timeout: time.Duration(l.Timeout) * time.Second,
+	})
+	result.UnlockTime = time.Since(unlockStart)
+	if err != nil {
+		return err
+	}
+	result.Unlocked = true
+	return nil
+}
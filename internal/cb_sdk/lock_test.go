@@ -0,0 +1,51 @@
+package cb_sdk
+
+import (
+	"errors"
+	"github.com/couchbase/gocb/v2"
+	"testing"
+	"time"
+)
+
+func TestLockDocumentContention(t *testing.T) {
+	cConfig := &ClusterConfig{
+		Username:         "Administrator",
+		Password:         "password",
+		ConnectionString: "couchbases://172.23.100.12",
+	}
+
+	cmObj := ConfigConnectionManager()
+	c, err := cmObj.GetCollection(cConfig, "lol", "_default", "_default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Collection.Upsert("lock-contention", map[string]string{"name": "lock"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// a mutation against the locked document fails with ErrDocumentLocked instead of being retried until the
+	// timeout, so the document keeps the CAS it was locked with.
+	l := &LockOptions{LockTime: 15, ContentionAttempts: 1, Timeout: 10}
+	start := time.Now()
+	result, err := LockDocument(c.Collection, "lock-contention", l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DocumentLockedErrors != 1 || time.Since(start) >= time.Duration(l.Timeout)*time.Second {
+		t.Fatalf("expected the contention to fail with %v, got %+v after %v", gocb.ErrDocumentLocked, result,
+			time.Since(start))
+	}
+	if err := UnlockDocument(c.Collection, "lock-contention", l, &result); err != nil || !result.Unlocked {
+		t.Fatalf("expected the document to be unlocked with the held CAS, got %v", err)
+	}
+	if _, err := c.Collection.Remove("lock-contention", nil); err != nil && !errors.Is(err, gocb.ErrDocumentNotFound) {
+		t.Fatal(err)
+	}
+}
+
+func TestLockedFailFastRetryStrategy(t *testing.T) {
+	strategy := lockedFailFastRetryStrategy{gocb.NewBestEffortRetryStrategy(nil)}
+	if action := strategy.RetryAfter(nil, gocb.KVLockedRetryReason); action.Duration() != 0 {
+		t.Fatalf("expected a locked document not to be retried, got %v", action.Duration())
+	}
+}
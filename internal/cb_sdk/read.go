@@ -0,0 +1,96 @@
+package cb_sdk
+
+import (
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"time"
+)
+
+// ReadDocumentResult holds the outcome of reading a document using one of the read modes.
+type ReadDocumentResult struct {
+	ReadMode  string
+	Cas       gocb.Cas
+	IsReplica bool
+	Exists    bool
+	Copies    int64
+}
+
+// ReadDocument reads a document using ReadOptions.ReadMode. For getAllReplicas the returned CAS is the one of
+// the active copy if it was received, else of the first replica copy. For exists a document which does not exist
+// fails with gocb.ErrDocumentNotFound, as it does for the other read modes.
+func ReadDocument(collection *gocb.Collection, docId string, r *ReadOptions) (ReadDocumentResult, error) {
+	result := ReadDocumentResult{ReadMode: r.ReadMode}
+	timeout := time.Duration(r.Timeout) * time.Second
+
+	switch r.ReadMode {
+	case ReadModeGetAndTouch:
+		getResult, err := collection.GetAndTouch(docId, time.Duration(r.Expiry)*time.Second,
+			&gocb.GetAndTouchOptions{
+				Timeout: timeout,
+			})
+		if err != nil {
+			return result, err
+		}
+		result.Cas = getResult.Cas()
+		result.Exists = true
+
+	case ReadModeExists:
+		existsResult, err := collection.Exists(docId, &gocb.ExistsOptions{
+			Timeout: timeout,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Cas = existsResult.Cas()
+		result.Exists = existsResult.Exists()
+		if !result.Exists {
+			return result, gocb.ErrDocumentNotFound
+		}
+
+	case ReadModeGetAnyReplica:
+		replicaResult, err := collection.GetAnyReplica(docId, &gocb.GetAnyReplicaOptions{
+			Timeout: timeout,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Cas = replicaResult.Cas()
+		result.IsReplica = replicaResult.IsReplica()
+		result.Exists = true
+
+	case ReadModeGetAllReplicas:
+		replicasResult, err := collection.GetAllReplicas(docId, &gocb.GetAllReplicaOptions{
+			Timeout: timeout,
+		})
+		if err != nil {
+			return result, err
+		}
+		for replicaResult := replicasResult.Next(); replicaResult != nil; replicaResult = replicasResult.Next() {
+			if result.Copies == 0 || !replicaResult.IsReplica() {
+				result.Cas = replicaResult.Cas()
+				result.IsReplica = replicaResult.IsReplica()
+			}
+			result.Copies++
+		}
+		if err := replicasResult.Close(); err != nil {
+			return result, err
+		}
+		if result.Copies == 0 {
+			return result, task_errors.ErrNoReplicaCopies
+		}
+		result.Exists = true
+
+	default:
+		getResult, err := collection.Get(docId, &gocb.GetOptions{
+			Project: r.Project,
+			Timeout: timeout,
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Cas = getResult.Cas()
+		result.Exists = true
+	}
+
+	return result, nil
+}
@@ -2,11 +2,13 @@ package cb_sdk
 
 import (
 	"github.com/couchbase/gocb/v2"
+	"sync"
 )
 
 type ScopeObject struct {
 	scope       *gocb.Scope                  `json:"-"`
 	collections map[string]*CollectionObject `json:"-"`
+	lock        sync.Mutex                   `json:"-"`
 }
 
 func (s *ScopeObject) setCollection(collectionName string, c *CollectionObject) {
@@ -15,6 +17,8 @@ func (s *ScopeObject) setCollection(collectionName string, c *CollectionObject)
 
 func (s *ScopeObject) getCollection(collectionName string) (*CollectionObject,
 	error) {
+	defer s.lock.Unlock()
+	s.lock.Lock()
 	_, ok := s.collections[collectionName]
 	if ok {
 		return s.collections[collectionName], nil
@@ -27,3 +31,10 @@ func (s *ScopeObject) getCollection(collectionName string) (*CollectionObject,
 	}
 	return s.collections[collectionName], nil
 }
+
+// removeCollection removes a collection so that it is opened again by the next task using it.
+func (s *ScopeObject) removeCollection(collectionName string) {
+	defer s.lock.Unlock()
+	s.lock.Lock()
+	delete(s.collections, collectionName)
+}
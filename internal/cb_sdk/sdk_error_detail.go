@@ -0,0 +1,90 @@
+package cb_sdk
+
+import (
+	"errors"
+	"github.com/couchbase/gocb/v2"
+	"strings"
+)
+
+// SDKErrorDetail is the context of a failed operation extracted from the error returned by the SDK. LastDispatchedTo
+// is the node the operation was last sent to, and RetryReasons why the SDK retried it before failing.
+type SDKErrorDetail struct {
+	Exception          string   `json:"exception" doc:"true"`
+	RetryReasons       []string `json:"retryReasons,omitempty" doc:"true"`
+	RetryAttempts      uint32   `json:"retryAttempts,omitempty" doc:"true"`
+	StatusCode         int      `json:"statusCode,omitempty" doc:"true"`
+	Status             string   `json:"status,omitempty" doc:"true"`
+	ErrorContext       string   `json:"errorContext,omitempty" doc:"true"`
+	LastDispatchedTo   string   `json:"lastDispatchedTo,omitempty" doc:"true"`
+	LastDispatchedFrom string   `json:"lastDispatchedFrom,omitempty" doc:"true"`
+	LastConnectionID   string   `json:"lastConnectionId,omitempty" doc:"true"`
+	TimeObserved       int64    `json:"timeObserved,omitempty" doc:"true"`
+}
+
+func retryReasons(reasons []gocb.RetryReason) []string {
+	var descriptions []string
+	for _, reason := range reasons {
+		descriptions = append(descriptions, reason.Description())
+	}
+	return descriptions
+}
+
+// GetSDKErrorDetail returns the detail of an error returned by the SDK. Only the exception is set for errors
+// carrying no context.
+func GetSDKErrorDetail(err error) SDKErrorDetail {
+	exception, _ := CheckSDKException(err)
+	detail := SDKErrorDetail{Exception: exception}
+
+	var kvErr *gocb.KeyValueError
+	var timeoutErr *gocb.TimeoutError
+	var queryErr *gocb.QueryError
+	var searchErr *gocb.SearchError
+	var analyticsErr *gocb.AnalyticsError
+	var httpErr *gocb.HTTPError
+	switch {
+	case errors.As(err, &kvErr):
+		detail.RetryReasons, detail.RetryAttempts = retryReasons(kvErr.RetryReasons), kvErr.RetryAttempts
+		detail.StatusCode, detail.Status = int(kvErr.StatusCode), kvErr.ErrorName
+		detail.ErrorContext = strings.TrimSpace(kvErr.ErrorDescription + " " + kvErr.Context)
+		detail.LastDispatchedTo, detail.LastDispatchedFrom = kvErr.LastDispatchedTo, kvErr.LastDispatchedFrom
+		detail.LastConnectionID = kvErr.LastConnectionID
+	case errors.As(err, &timeoutErr):
+		detail.RetryReasons, detail.RetryAttempts = retryReasons(timeoutErr.RetryReasons), timeoutErr.RetryAttempts
+		detail.ErrorContext = timeoutErr.OperationID
+		detail.LastDispatchedTo, detail.LastDispatchedFrom = timeoutErr.LastDispatchedTo, timeoutErr.LastDispatchedFrom
+		detail.LastConnectionID = timeoutErr.LastConnectionID
+		detail.TimeObserved = timeoutErr.TimeObserved.Milliseconds()
+	case errors.As(err, &queryErr):
+		detail.RetryReasons, detail.RetryAttempts = retryReasons(queryErr.RetryReasons), queryErr.RetryAttempts
+		detail.StatusCode, detail.ErrorContext = queryErr.HTTPStatusCode, queryErr.ErrorText
+		detail.LastDispatchedTo = queryErr.Endpoint
+	case errors.As(err, &searchErr):
+		detail.RetryReasons, detail.RetryAttempts = retryReasons(searchErr.RetryReasons), searchErr.RetryAttempts
+		detail.StatusCode, detail.ErrorContext = searchErr.HTTPStatusCode, searchErr.ErrorText
+		detail.LastDispatchedTo = searchErr.Endpoint
+	case errors.As(err, &analyticsErr):
+		detail.RetryReasons, detail.RetryAttempts = retryReasons(analyticsErr.RetryReasons), analyticsErr.RetryAttempts
+		detail.StatusCode, detail.ErrorContext = analyticsErr.HTTPStatusCode, analyticsErr.ErrorText
+		detail.LastDispatchedTo = analyticsErr.Endpoint
+	case errors.As(err, &httpErr):
+		detail.RetryReasons, detail.RetryAttempts = retryReasons(httpErr.RetryReasons), httpErr.RetryAttempts
+		detail.StatusCode, detail.ErrorContext = int(httpErr.StatusCode), httpErr.ErrorText
+		detail.LastDispatchedTo = httpErr.Endpoint
+	}
+	return detail
+}
+
+// Classification classifies the error by its exception and the last reason the SDK retried the operation for, as
+// in "ambiguous timeout : KV_NOT_MY_VBUCKET".
+func (d *SDKErrorDetail) Classification() string {
+	if len(d.RetryReasons) == 0 {
+		return d.Exception
+	}
+	return d.Exception + " : " + d.RetryReasons[len(d.RetryReasons)-1]
+}
+
+// HasContext returns true if the SDK returned any context with the error.
+func (d *SDKErrorDetail) HasContext() bool {
+	return len(d.RetryReasons) > 0 || d.RetryAttempts > 0 || d.StatusCode != 0 || d.Status != "" ||
+		d.ErrorContext != "" || d.LastDispatchedTo != ""
+}
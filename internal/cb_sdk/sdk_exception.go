@@ -3,53 +3,73 @@ package cb_sdk
 import (
 	"errors"
 	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
 )
 
-func registeredErrors() map[error]struct{} {
-	return map[error]struct{}{
-		gocb.ErrCasMismatch:                    {},
-		gocb.ErrCollectionNotFound:             {},
-		gocb.ErrScopeNotFound:                  {},
-		gocb.ErrDecodingFailure:                {},
-		gocb.ErrDocumentExists:                 {},
-		gocb.ErrDocumentNotFound:               {},
-		gocb.ErrDocumentLocked:                 {},
-		gocb.ErrDurabilityAmbiguous:            {},
-		gocb.ErrDurabilityImpossible:           {},
-		gocb.ErrUnambiguousTimeout:             {},
-		gocb.ErrDurableWriteInProgress:         {},
-		gocb.ErrFeatureNotAvailable:            {},
-		gocb.ErrTimeout:                        {},
-		gocb.ErrAmbiguousTimeout:               {},
-		gocb.ErrUnambiguousTimeout:             {},
-		gocb.ErrPathNotFound:                   {},
-		gocb.ErrPathInvalid:                    {},
-		gocb.ErrPathExists:                     {},
-		gocb.ErrRequestCanceled:                {},
-		gocb.ErrTemporaryFailure:               {},
-		gocb.ErrValueTooLarge:                  {},
-		gocb.ErrIndexExists:                    {},
-		gocb.ErrIndexFailure:                   {},
-		gocb.ErrIndexNotFound:                  {},
-		gocb.ErrAttemptNotFoundOnQuery:         {},
-		gocb.ErrPlanningFailure:                {},
-		gocb.ErrCasMismatch:                    {},
-		gocb.ErrBucketNotFound:                 {},
-		gocb.ErrBucketNotFlushable:             {},
-		gocb.ErrBucketExists:                   {},
-		gocb.ErrAuthenticationFailure:          {},
-		gocb.ErrDurableWriteReCommitInProgress: {},
-		gocb.ErrDurabilityLevelNotAvailable:    {},
+// registeredErrors are the SDK sentinel errors, and errors of replica verification, failures are classified by. A
+// sentinel wrapping another one, like the ambiguous and unambiguous timeouts wrapping ErrTimeout, is listed before it
+// so that the most specific matches.
+func registeredErrors() []error {
+	return []error{
+		gocb.ErrCasMismatch,
+		gocb.ErrCollectionNotFound,
+		gocb.ErrScopeNotFound,
+		gocb.ErrDecodingFailure,
+		gocb.ErrDocumentExists,
+		gocb.ErrDocumentNotFound,
+		gocb.ErrDocumentLocked,
+		gocb.ErrDurabilityAmbiguous,
+		gocb.ErrDurabilityImpossible,
+		gocb.ErrDurableWriteInProgress,
+		gocb.ErrDurableWriteReCommitInProgress,
+		gocb.ErrDurabilityLevelNotAvailable,
+		gocb.ErrFeatureNotAvailable,
+		gocb.ErrAmbiguousTimeout,
+		gocb.ErrUnambiguousTimeout,
+		gocb.ErrTimeout,
+		gocb.ErrPathNotFound,
+		gocb.ErrPathInvalid,
+		gocb.ErrPathExists,
+		gocb.ErrRequestCanceled,
+		gocb.ErrTemporaryFailure,
+		gocb.ErrValueTooLarge,
+		gocb.ErrIndexExists,
+		gocb.ErrIndexFailure,
+		gocb.ErrIndexNotFound,
+		gocb.ErrAttemptNotFoundOnQuery,
+		gocb.ErrPlanningFailure,
+		gocb.ErrBucketNotFound,
+		gocb.ErrBucketNotFlushable,
+		gocb.ErrBucketExists,
+		gocb.ErrAuthenticationFailure,
+		task_errors.ErrNoReplicaCopies,
+		task_errors.ErrUnderReplicated,
 	}
 }
 
 // CheckSDKException returns SDK Exception on possible match.
 func CheckSDKException(err error) (string, string) {
 
-	for e, _ := range registeredErrors() {
+	for _, e := range registeredErrors() {
 		if errors.Is(err, e) {
 			return e.Error(), err.Error()
 		}
 	}
 	return "Unknown Exception", err.Error()
 }
+
+// IsAmbiguousError returns true if the outcome of a mutation failing with err is unknown, the mutation having
+// possibly been applied.
+func IsAmbiguousError(err error) bool {
+	return errors.Is(err, gocb.ErrDurabilityAmbiguous) || errors.Is(err, gocb.ErrAmbiguousTimeout)
+}
+
+// IsCasMismatch returns true if a CAS guarded mutation failed with err because the document was mutated concurrently.
+func IsCasMismatch(err error) bool {
+	return errors.Is(err, gocb.ErrCasMismatch)
+}
+
+// IsDocumentExists returns true if an insert failed with err because the document was created concurrently.
+func IsDocumentExists(err error) bool {
+	return errors.Is(err, gocb.ErrDocumentExists)
+}
@@ -0,0 +1,48 @@
+package cb_sdk
+
+import (
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"testing"
+	"time"
+)
+
+func TestSDKErrorDetail(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		if exception, _ := CheckSDKException(gocb.ErrAmbiguousTimeout); exception != gocb.ErrAmbiguousTimeout.Error() {
+			t.Fatalf("expected ambiguous timeout, got %s", exception)
+		}
+	}
+
+	timeoutErr := fmt.Errorf("upsert failed | %w", &gocb.TimeoutError{
+		InnerError:       gocb.ErrAmbiguousTimeout,
+		TimeObserved:     2500 * time.Millisecond,
+		RetryReasons:     []gocb.RetryReason{gocb.KVTemporaryFailureRetryReason, gocb.KVNotMyVBucketRetryReason},
+		RetryAttempts:    7,
+		LastDispatchedTo: "10.0.0.2:11210",
+	})
+	detail := GetSDKErrorDetail(timeoutErr)
+	if detail.Exception != gocb.ErrAmbiguousTimeout.Error() || detail.LastDispatchedTo != "10.0.0.2:11210" ||
+		detail.RetryAttempts != 7 || detail.TimeObserved != 2500 {
+		t.Fatalf("unexpected timeout detail %+v", detail)
+	}
+	if c := detail.Classification(); c != "ambiguous timeout : KV_NOT_MY_VBUCKET" {
+		t.Fatalf("unexpected classification %s", c)
+	}
+
+	detail = GetSDKErrorDetail(&gocb.KeyValueError{
+		InnerError:       gocb.ErrDocumentNotFound,
+		StatusCode:       1,
+		ErrorName:        "KEY_ENOENT",
+		LastDispatchedTo: "10.0.0.3:11210",
+	})
+	if detail.Exception != gocb.ErrDocumentNotFound.Error() || detail.Status != "KEY_ENOENT" ||
+		detail.StatusCode != 1 || !detail.HasContext() || detail.Classification() != detail.Exception {
+		t.Fatalf("unexpected key value detail %+v", detail)
+	}
+
+	detail = GetSDKErrorDetail(fmt.Errorf("not an sdk error"))
+	if detail.Exception != "Unknown Exception" || detail.HasContext() {
+		t.Fatalf("unexpected detail %+v", detail)
+	}
+}
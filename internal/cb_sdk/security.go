@@ -0,0 +1,111 @@
+package cb_sdk
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"os"
+	"strings"
+)
+
+// SecurityConfig specifies how the TLS connection to a cluster is verified and authenticated. Certificates and keys
+// are PEM encoded and supplied either inline or as a path to a file on the Sirius host.
+type SecurityConfig struct {
+	// VerifyCertificate verifies the certificate of the cluster. The certificate is not verified by default.
+	VerifyCertificate bool `json:"verifyCertificate,omitempty" doc:"true"`
+	// RootCA is the bundle of root certificates to trust, which also enables verification.
+	RootCA     string `json:"rootCA,omitempty" doc:"true"`
+	RootCAPath string `json:"rootCAPath,omitempty" doc:"true"`
+	// ClientCertificate and ClientKey authenticate Sirius to the cluster in place of username and password.
+	ClientCertificate     string `json:"clientCertificate,omitempty" doc:"true"`
+	ClientCertificatePath string `json:"clientCertificatePath,omitempty" doc:"true"`
+	ClientKey             string `json:"clientKey,omitempty" doc:"true"`
+	ClientKeyPath         string `json:"clientKeyPath,omitempty" doc:"true"`
+}
+
+// UsesClientCertificate returns true if the cluster is authenticated with a client certificate.
+func (s *SecurityConfig) UsesClientCertificate() bool {
+	return s.ClientCertificate != "" || s.ClientCertificatePath != "" || s.ClientKey != "" || s.ClientKeyPath != ""
+}
+
+// validate checks that every certificate and key is supplied at most once and a client certificate comes with its key.
+func (s *SecurityConfig) validate(connectionString string) error {
+	if s.RootCA != "" && s.RootCAPath != "" {
+		return fmt.Errorf("%w : only one of rootCA and rootCAPath can be set", task_errors.ErrInvalidSecurityConfig)
+	}
+	if s.ClientCertificate != "" && s.ClientCertificatePath != "" {
+		return fmt.Errorf("%w : only one of clientCertificate and clientCertificatePath can be set",
+			task_errors.ErrInvalidSecurityConfig)
+	}
+	if s.ClientKey != "" && s.ClientKeyPath != "" {
+		return fmt.Errorf("%w : only one of clientKey and clientKeyPath can be set",
+			task_errors.ErrInvalidSecurityConfig)
+	}
+	if s.UsesClientCertificate() {
+		if (s.ClientCertificate == "" && s.ClientCertificatePath == "") || (s.ClientKey == "" && s.ClientKeyPath == "") {
+			return fmt.Errorf("%w : client certificate and client key are both required",
+				task_errors.ErrInvalidSecurityConfig)
+		}
+		if !strings.HasPrefix(connectionString, "couchbases://") {
+			return fmt.Errorf("%w : client certificate requires a couchbases:// connection string",
+				task_errors.ErrInvalidSecurityConfig)
+		}
+	}
+	return nil
+}
+
+// readPEM returns the inline PEM or reads it from path.
+func readPEM(inline, path string) ([]byte, error) {
+	if inline != "" {
+		return []byte(inline), nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidSecurityConfig, err.Error())
+	}
+	return b, nil
+}
+
+// gocbSecurityConfig returns the gocb.SecurityConfig for the cluster.
+func (s *SecurityConfig) gocbSecurityConfig() (gocb.SecurityConfig, error) {
+	if s.RootCA == "" && s.RootCAPath == "" {
+		return gocb.SecurityConfig{TLSSkipVerify: !s.VerifyCertificate}, nil
+	}
+	b, err := readPEM(s.RootCA, s.RootCAPath)
+	if err != nil {
+		return gocb.SecurityConfig{}, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return gocb.SecurityConfig{}, fmt.Errorf("%w : no certificate found in root CA",
+			task_errors.ErrInvalidSecurityConfig)
+	}
+	return gocb.SecurityConfig{TLSRootCAs: pool}, nil
+}
+
+// authenticator returns a CertificateAuthenticator if a client certificate is given and a PasswordAuthenticator
+// otherwise.
+func (c *ClusterConfig) authenticator() (gocb.Authenticator, error) {
+	s := c.SecurityConfig
+	if !s.UsesClientCertificate() {
+		return gocb.PasswordAuthenticator{
+			Username: c.Username,
+			Password: c.Password,
+		}, nil
+	}
+	certificate, err := readPEM(s.ClientCertificate, s.ClientCertificatePath)
+	if err != nil {
+		return nil, err
+	}
+	key, err := readPEM(s.ClientKey, s.ClientKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	clientCertificate, err := tls.X509KeyPair(certificate, key)
+	if err != nil {
+		return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidSecurityConfig, err.Error())
+	}
+	return gocb.CertificateAuthenticator{ClientCertificate: &clientCertificate}, nil
+}
@@ -0,0 +1,106 @@
+package cb_sdk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func selfSignedCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sirius"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+}
+
+func TestSecurityConfig(t *testing.T) {
+	certificate, key := selfSignedCertificate(t)
+	keyPath := filepath.Join(t.TempDir(), "client.key")
+	if err := os.WriteFile(keyPath, []byte(key), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []*ClusterConfig{
+		{ConnectionString: "couchbases://host", Username: "u", Password: "p",
+			SecurityConfig: SecurityConfig{RootCA: certificate, RootCAPath: "ca.pem"}},
+		{ConnectionString: "couchbases://host", SecurityConfig: SecurityConfig{ClientCertificate: certificate}},
+		{ConnectionString: "couchbase://host", SecurityConfig: SecurityConfig{ClientCertificate: certificate,
+			ClientKey: key}},
+	} {
+		if err := ValidateClusterConfig(c); !errors.Is(err, task_errors.ErrInvalidSecurityConfig) {
+			t.Fatalf("expected invalid security config, got %v", err)
+		}
+	}
+	if err := ValidateClusterConfig(&ClusterConfig{ConnectionString: "couchbases://host"}); !errors.Is(err,
+		task_errors.ErrCredentialMissing) {
+		t.Fatalf("expected missing credentials, got %v", err)
+	}
+
+	c := &ClusterConfig{ConnectionString: "couchbases://host", SecurityConfig: SecurityConfig{
+		RootCA:            certificate,
+		ClientCertificate: certificate,
+		ClientKeyPath:     keyPath,
+	}}
+	if err := ValidateClusterConfig(c); err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := c.authenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, ok := authenticator.(gocb.CertificateAuthenticator); !ok || a.ClientCertificate == nil {
+		t.Fatalf("expected certificate authenticator, got %T", authenticator)
+	}
+	securityConfig, err := c.SecurityConfig.gocbSecurityConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if securityConfig.TLSSkipVerify || securityConfig.TLSRootCAs == nil {
+		t.Fatal("expected verification against the root CA")
+	}
+
+	c.SecurityConfig.ClientKeyPath = ""
+	c.SecurityConfig.ClientKey = "not a key"
+	if _, err := c.authenticator(); !errors.Is(err, task_errors.ErrInvalidSecurityConfig) {
+		t.Fatalf("expected invalid client key, got %v", err)
+	}
+	c.SecurityConfig.RootCA = "not a certificate"
+	if _, err := c.SecurityConfig.gocbSecurityConfig(); !errors.Is(err, task_errors.ErrInvalidSecurityConfig) {
+		t.Fatalf("expected invalid root CA, got %v", err)
+	}
+
+	if securityConfig, _ = (&SecurityConfig{}).gocbSecurityConfig(); !securityConfig.TLSSkipVerify {
+		t.Fatal("expected verification to be skipped by default")
+	}
+	if securityConfig, _ = (&SecurityConfig{VerifyCertificate: true}).gocbSecurityConfig(); securityConfig.TLSSkipVerify {
+		t.Fatal("expected verification with verifyCertificate")
+	}
+}
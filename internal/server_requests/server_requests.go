@@ -3,6 +3,7 @@ package server_requests
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
 	"github.com/couchbaselabs/sirius/internal/tasks"
 	"log"
 	"os"
@@ -14,6 +15,7 @@ import (
 const ServerRequestsPath = "./internal/server_requests/server_requests_logs"
 const ServerRequestFileName = "server_requests"
 const SnapShortTime = 10
+const HealthCheckTime = 30
 
 // ServerRequests will have a lookup of unique identifier of a cluster which contains requests for different operation
 // for that particular cluster.
@@ -59,6 +61,7 @@ func NewServerRequests() *ServerRequests {
 		sr.Identifiers = make(map[string]struct{})
 	}
 	go sr.saveRequestsIntoFilePeriodically()
+	go sr.checkConnectionsPeriodically()
 	return sr
 }
 
@@ -82,6 +85,24 @@ func (sr *ServerRequests) saveRequestsIntoFilePeriodically() {
 	}
 }
 
+// checkConnectionsPeriodically runs a health check of the cluster connections of every tasks.Request, evicting the
+// unhealthy ones so that they are reconnected by the next task.
+func (sr *ServerRequests) checkConnectionsPeriodically() {
+	d := time.NewTicker(HealthCheckTime * time.Second)
+	for {
+		select {
+		case _ = <-d.C:
+			sr.RequestLookup.Range(func(key, value any) bool {
+				r, ok := value.(*tasks.Request)
+				if ok && !r.ContextClosed() {
+					r.CheckConnections()
+				}
+				return true
+			})
+		}
+	}
+}
+
 // ReadServerRequestsFromFile will try to read identifiers from disk pointing towards tasks.Request
 func ReadServerRequestsFromFile() (*ServerRequests, error) {
 	cwd, err := os.Getwd()
@@ -208,6 +229,23 @@ func (sr *ServerRequests) GetRequestOfIdentifier(identifier string) (*tasks.Requ
 	return nil, fmt.Errorf("unknown identifer or request")
 }
 
+// ConnectionStates returns the state of the cluster connections of the tasks.Request of an identifier. If refresh is
+// true, a health check is run first.
+func (sr *ServerRequests) ConnectionStates(identifier string, refresh bool) ([]cb_sdk.ClusterState, error) {
+	r, ok := sr.RequestLookup.Load(identifier)
+	if !ok {
+		return nil, fmt.Errorf("no session for %s", identifier)
+	}
+	req, ok := r.(*tasks.Request)
+	if !ok || req == nil {
+		return nil, fmt.Errorf("unknown identifer or request")
+	}
+	if refresh {
+		return req.CheckConnections(), nil
+	}
+	return req.ConnectionStates(), nil
+}
+
 func (sr *ServerRequests) ClearIdentifierAndRequest(identifier string) error {
 	if sr.checkIfExists(identifier) {
 		r, _ := sr.RequestLookup.Load(identifier)
@@ -27,6 +27,7 @@ func (r *Register) RegisteredTasks() map[string]TaskRegister {
 		"/validate":               {"POST", &bulk_loading_cb.ValidateTask{}},
 		"/result":                 {"POST", &util_sirius.TaskResult{}},
 		"/clear_data":             {"POST", &util_sirius.ClearTask{}},
+		"/connection-status":      {"POST", &util_sirius.ConnectionStatus{}},
 		"/bulk-read":              {"POST", &bulk_loading_cb.ReadTask{}},
 		"/single-create":          {"POST", &key_based_loading_cb.SingleInsertTask{}},
 		"/single-delete":          {"POST", &key_based_loading_cb.SingleDeleteTask{}},
@@ -48,6 +49,37 @@ func (r *Register) RegisteredTasks() map[string]TaskRegister {
 		"/single-sub-doc-read":    {"POST", &key_based_loading_cb.SingleSubDocRead{}},
 		"/single-doc-validate":    {"POST", &key_based_loading_cb.SingleValidate{}},
 		"/warmup-bucket":          {"POST", &util_cb.BucketWarmUpTask{}},
+		"/bulk-increment":         {"POST", &bulk_loading_cb.IncrementTask{}},
+		"/bulk-decrement":         {"POST", &bulk_loading_cb.DecrementTask{}},
+		"/bulk-append":            {"POST", &bulk_loading_cb.AppendTask{}},
+		"/bulk-prepend":           {"POST", &bulk_loading_cb.PrependTask{}},
+		"/single-increment":       {"POST", &key_based_loading_cb.SingleIncrementTask{}},
+		"/single-decrement":       {"POST", &key_based_loading_cb.SingleDecrementTask{}},
+		"/single-append":          {"POST", &key_based_loading_cb.SingleAppendTask{}},
+		"/single-prepend":         {"POST", &key_based_loading_cb.SinglePrependTask{}},
+		"/bulk-lock":              {"POST", &bulk_loading_cb.LockTask{}},
+		"/single-lock":            {"POST", &key_based_loading_cb.SingleLockTask{}},
+		"/single-unlock":          {"POST", &key_based_loading_cb.SingleUnlockTask{}},
+		"/bulk-scan":              {"POST", &bulk_loading_cb.ScanTask{}},
+		"/bulk-transaction":       {"POST", &bulk_loading_cb.TransactionTask{}},
+		"/run-search-query":       {"POST", &bulk_query_cb.SearchTask{}},
+		"/run-analytics-query":    {"POST", &bulk_query_cb.AnalyticsTask{}},
+		"/create-index":           {"POST", &util_cb.IndexTask{}},
+		"/build-index":            {"POST", &util_cb.IndexTask{}},
+		"/watch-index":            {"POST", &util_cb.IndexTask{}},
+		"/drop-index":             {"POST", &util_cb.IndexTask{}},
+		"/list-index":             {"POST", &util_cb.IndexTask{}},
+		"/create-bucket":          {"POST", &util_cb.BucketTask{}},
+		"/drop-bucket":            {"POST", &util_cb.BucketTask{}},
+		"/flush-bucket":           {"POST", &util_cb.BucketTask{}},
+		"/create-scope":           {"POST", &util_cb.CollectionTask{}},
+		"/drop-scope":             {"POST", &util_cb.CollectionTask{}},
+		"/create-collection":      {"POST", &util_cb.CollectionTask{}},
+		"/drop-collection":        {"POST", &util_cb.CollectionTask{}},
+		"/bulk-fan-out":           {"POST", &bulk_loading_cb.FanOutTask{}},
+		"/run-plan":               {"POST", &util_sirius.PlanTask{}},
+		"/schedule-task":          {"POST", &util_sirius.ScheduleTask{}},
+		"/verify-durability":      {"POST", &bulk_loading_cb.VerifyDurabilityTask{}},
 	}
 }
 
@@ -56,6 +88,12 @@ func (r *Register) HelperStruct() map[string]any {
 		"clusterConfig":               &cb_sdk.ClusterConfig{},
 		"compressionConfig":           &cb_sdk.CompressionConfig{},
 		"timeoutsConfig":              &cb_sdk.TimeoutsConfig{},
+		"securityConfig":              &cb_sdk.SecurityConfig{},
+		"circuitBreakerConfig":        &cb_sdk.CircuitBreakerConfig{},
+		"orphanReporterConfig":        &cb_sdk.OrphanReporterConfig{},
+		"thresholdLoggingConfig":      &cb_sdk.ThresholdLoggingConfig{},
+		"retryStrategyConfig":         &cb_sdk.RetryStrategyConfig{},
+		"ioConfig":                    &cb_sdk.IoConfig{},
 		"operationConfig":             &bulk_loading_cb.OperationConfig{},
 		"insertOptions":               &cb_sdk.InsertOptions{},
 		"removeOptions":               &cb_sdk.RemoveOptions{},
@@ -64,9 +102,15 @@ func (r *Register) HelperStruct() map[string]any {
 		"singleOperationConfig":       &key_based_loading_cb.SingleOperationConfig{},
 		"bulkError":                   &task_result.FailedDocument{},
 		"retriedError":                &task_result.FailedDocument{},
+		"errorDetail":                 &cb_sdk.SDKErrorDetail{},
+		"nodeResult":                  &task_result.NodeResult{},
 		"singleResult":                &task_result.SingleOperationResult{},
 		"queryOperationConfig":        &cb_sdk.QueryOperationConfig{},
+		"queryDefinition":             &cb_sdk.QueryDefinition{},
+		"queryParameter":              &cb_sdk.QueryParameter{},
 		"exceptions":                  &bulk_loading_cb.Exceptions{},
+		"retryPolicy":                 &bulk_loading_cb.RetryPolicy{},
+		"exceptionRetry":              &bulk_loading_cb.ExceptionRetry{},
 		"mutateInOptions":             &cb_sdk.MutateInOptions{},
 		"insertSpecOptions":           &cb_sdk.InsertSpecOptions{},
 		"removeSpecOptions":           &cb_sdk.RemoveSpecOptions{},
@@ -75,6 +119,36 @@ func (r *Register) HelperStruct() map[string]any {
 		"replaceSpecOptions":          &cb_sdk.ReplaceSpecOptions{},
 		"singleSubDocOperationConfig": &key_based_loading_cb.SingleSubDocOperationConfig{},
 		"sdkTimings":                  &task_result.SDKTiming{},
+		"counterOptions":              &cb_sdk.CounterOptions{},
+		"binaryOptions":               &cb_sdk.BinaryOptions{},
+		"lockOptions":                 &cb_sdk.LockOptions{},
+		"unlockOptions":               &cb_sdk.UnlockOptions{},
+		"lockTimings":                 &task_result.LockTimings{},
+		"readOptions":                 &cb_sdk.ReadOptions{},
+		"readResult":                  &task_result.ReadResult{},
+		"scanOptions":                 &cb_sdk.ScanOptions{},
+		"scanResult":                  &task_result.ScanResult{},
+		"searchQueryResult":           &task_result.SearchQueryResult{},
+		"transactionOptions":          &cb_sdk.TransactionOptions{},
+		"transactions":                &task_result.TransactionResult{},
+		"searchOperationConfig":       &cb_sdk.SearchOperationConfig{},
+		"analyticsOperationConfig":    &cb_sdk.AnalyticsOperationConfig{},
+		"queryShapeResult":            &task_result.QueryShapeResult{},
+		"queryMismatch":               &task_result.QueryMismatch{},
+		"indexOptions":                &cb_sdk.IndexOptions{},
+		"indexDefinition":             &cb_sdk.IndexDefinition{},
+		"indexOutcome":                &task_result.IndexOutcome{},
+		"bucketOptions":               &cb_sdk.BucketOptions{},
+		"collectionOptions":           &cb_sdk.CollectionOptions{},
+		"collectionResult":            &task_result.CollectionResult{},
+		"planStep":                    &util_sirius.PlanStep{},
+		"stepCondition":               &util_sirius.StepCondition{},
+		"planStepResult":              &task_result.PlanStepResult{},
+		"scheduledRun":                &task_result.ScheduledRun{},
+		"replicaVerification":         &cb_sdk.ReplicaVerification{},
+		"durabilityResult":            &task_result.DurabilityResult{},
+		"casOptions":                  &cb_sdk.CasOptions{},
+		"casResult":                   &task_result.CasResult{},
 	}
 
 }
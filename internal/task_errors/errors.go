@@ -8,11 +8,15 @@ var (
 	ErrParsingClusterConfig               = errors.New("unable to parse clusterConfig")
 	ErrCredentialMissing                  = errors.New("missing credentials for authentication")
 	ErrInvalidConnectionString            = errors.New("empty or invalid connection string")
+	ErrInvalidSecurityConfig              = errors.New("invalid security config")
+	ErrInvalidClusterOptions              = errors.New("invalid cluster options")
 	ErrParsingSingleOperationConfig       = errors.New("unable to parse SingleOperationConfig")
 	ErrParsingQueryConfig                 = errors.New("unable to parse QueryOperationConfig")
 	ErrParsingOperatingConfig             = errors.New("unable to parse operationConfig")
 	ErrMalformedOperationRange            = errors.New("operation start to end range is malformed")
+	ErrInvalidRetryPolicy                 = errors.New("invalid retry policy")
 	ErrParsingInsertOptions               = errors.New("unable to parse InsertOptions")
+	ErrParsingCasOptions                  = errors.New("unable to parse CasOptions")
 	ErrParsingTouchOptions                = errors.New("unable to parse TouchOptions")
 	ErrParsingRemoveOptions               = errors.New("unable to parse RemoveOptions")
 	ErrParsingReplaceOptions              = errors.New("unable to parse ReplaceOptions")
@@ -24,6 +28,55 @@ var (
 	ErrParsingReplaceSpecOptions          = errors.New("unable to parse ReplaceSpecOptions")
 	ErrParsingSingleSubDocOperationConfig = errors.New("unable to parse SingleSubDocOperationConfig")
 	ErrParsingMutateInOptions             = errors.New("unable to parse MutateInOptions")
+	ErrParsingCounterOptions              = errors.New("unable to parse CounterOptions")
+	ErrParsingBinaryOptions               = errors.New("unable to parse BinaryOptions")
+	ErrParsingLockOptions                 = errors.New("unable to parse LockOptions")
+	ErrInvalidLockTime                    = errors.New("lock time must be between 1 and 30 seconds")
+	ErrParsingUnlockOptions               = errors.New("unable to parse UnlockOptions")
+	ErrParsingReadOptions                 = errors.New("unable to parse ReadOptions")
+	ErrInvalidReadMode                    = errors.New("invalid read mode, expected get, getAndTouch, exists, getAnyReplica or getAllReplicas")
+	ErrProjectionNotSupported             = errors.New("projection is only supported with the get read mode")
+	ErrNoReplicaCopies                    = errors.New("no copy of the document found on active or replicas")
+	ErrUnderReplicated                    = errors.New("document is under replicated")
+	ErrParsingScanOptions                 = errors.New("unable to parse ScanOptions")
+	ErrInvalidScanType                    = errors.New("invalid scan type, expected range or sampling")
+	ErrScanMissingKey                     = errors.New("key expected to exist was not returned by scan")
+	ErrScanUnexpectedKey                  = errors.New("key returned by scan is not expected to exist")
+	ErrParsingTransactionOptions          = errors.New("unable to parse TransactionOptions")
+	ErrInvalidRatio                       = errors.New("ratio must lie between 0 and 1")
+	ErrTransactionRolledBack              = errors.New("transaction rolled back as per rollbackRatio")
+	ErrParsingSearchOperationConfig       = errors.New("unable to parse SearchOperationConfig")
+	ErrSearchIndexTimeout                 = errors.New("search index did not index the expected item count in time")
+	ErrNoSearchFields                     = errors.New("template does not define any field for search index")
+	ErrParsingAnalyticsOperationConfig    = errors.New("unable to parse AnalyticsOperationConfig")
+	ErrAnalyticsIngestionTimeout          = errors.New("analytics collection did not ingest the expected item count in time")
+	ErrEmptyQueryStatement                = errors.New("query statement is empty")
+	ErrInvalidQueryWeight                 = errors.New("query weight cannot be negative")
+	ErrInvalidScanConsistency             = errors.New("invalid scan consistency, expected not_bounded, request_plus or at_plus")
+	ErrMixedQueryParameters               = errors.New("query parameters must be either all named or all positional")
+	ErrParsingIndexOptions                = errors.New("unable to parse IndexOptions")
+	ErrInvalidIndexOptions                = errors.New("number of index replicas and partitions cannot be negative")
+	ErrIndexNameMissing                   = errors.New("name is required for a secondary index")
+	ErrInvalidIndexOperation              = errors.New("invalid index operation, expected createIndex, buildIndex, watchIndex, dropIndex or listIndex")
+	ErrParsingBucketOptions               = errors.New("unable to parse BucketOptions")
+	ErrInvalidBucketOptions               = errors.New("invalid bucket type, storage backend, eviction policy, compression mode, conflict resolution type or maxTTL")
+	ErrInvalidBucketOperation             = errors.New("invalid bucket operation, expected createBucket, dropBucket or flushBucket")
+	ErrParsingCollectionOptions           = errors.New("unable to parse CollectionOptions")
+	ErrInvalidNamePattern                 = errors.New("invalid name pattern, expected ranges as [start-end]")
+	ErrCollectionsMissing                 = errors.New("collections are required for collection operations")
+	ErrInvalidCollectionOperation         = errors.New("invalid collection operation, expected createScope, dropScope, createCollection or dropCollection")
+	ErrInvalidFanOutOperation             = errors.New("bulk operation cannot be fanned out across collections")
+	ErrNoCollectionsToFanOut              = errors.New("no collections found for the collections pattern")
+	ErrEmptyPlan                          = errors.New("plan has no steps")
+	ErrPlanStepNameMissing                = errors.New("name is required for every step of a plan")
+	ErrDuplicatePlanStep                  = errors.New("duplicate step name in plan")
+	ErrUnknownPlanStep                    = errors.New("step depends on a step which is not in the plan")
+	ErrPlanCycle                          = errors.New("steps of the plan have a cyclic dependency")
+	ErrInvalidPlanOperation               = errors.New("operation cannot be run as a step of a plan")
+	ErrInvalidPlanCondition               = errors.New("condition of a step can only refer to a step it depends on")
+	ErrInvalidCronExpression              = errors.New("invalid cron expression, expected minute, hour, day of month, month and day of week")
+	ErrInvalidSchedule                    = errors.New("invalid schedule")
+	ErrScheduledRunInterrupted            = errors.New("run interrupted before its task was configured")
 	ErrNilOperationConfig                 = errors.New("no operation found for the given offset")
 	ErrTaskingRetryFailed                 = errors.New("task is still in pending state before retrying")
 	ErrTaskInPendingState                 = errors.New("current task is still in progress")
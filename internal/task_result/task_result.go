@@ -9,15 +9,28 @@ import (
 	"github.com/couchbaselabs/sirius/internal/task_state"
 	"golang.org/x/sync/errgroup"
 	"log"
+	"math"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 )
 
 const (
-	ResultPath         = "./internal/task_result/task_result_logs"
-	ResultChannelLimit = 10000
+	ResultPath             = "./internal/task_result/task_result_logs"
+	ResultChannelLimit     = 10000
+	MaxLatencySamples      = 10000
+	MaxUnderReplicatedKeys = 10000
+	MaxLockedDocuments     = 10000
+)
+
+const (
+	TransactionCommitted  = "committed"
+	TransactionRolledBack = "rolledBack"
+	TransactionAmbiguous  = "ambiguous"
+	TransactionFailed     = "failed"
 )
 
 type SDKTiming struct {
@@ -32,6 +45,28 @@ type FailedDocument struct {
 	Cas         uint64    `json:"cas"  doc:"true"`
 	ErrorString string    `json:"errorString"  doc:"true"`
 	Offset      int64     `json:"Offset" doc:"false"`
+	// ErrorDetail is the context returned by the SDK with the error, if any.
+	ErrorDetail *cb_sdk.SDKErrorDetail `json:"errorDetail,omitempty" doc:"true"`
+	Node        string                 `json:"node,omitempty" doc:"true"`
+	VBucket     *uint16                `json:"vBucket,omitempty" doc:"true"`
+}
+
+// UnknownNode is the node of operations whose node could not be resolved.
+const UnknownNode = "unknown"
+
+// KeyLocator returns the vBucket of a document and the node hosting its active copy, the node being empty if the
+// vBucket has no active copy.
+type KeyLocator interface {
+	KeyLocation(docId string) (uint16, string, error)
+}
+
+// NodeResult aggregates the outcome of the operations dispatched to a node. Failures are counted by exception and
+// retry reason in Errors and by vBucket in VBuckets. Success only counts the sampled successful operations.
+type NodeResult struct {
+	Success  int64            `json:"success" doc:"true"`
+	Failure  int64            `json:"failure" doc:"true"`
+	Errors   map[string]int64 `json:"errors,omitempty" doc:"true"`
+	VBuckets map[uint16]int64 `json:"vBuckets,omitempty" doc:"true"`
 }
 
 type SingleOperationResult struct {
@@ -42,8 +77,168 @@ type SingleOperationResult struct {
 }
 
 type FailedQuery struct {
-	Query       string `json:"query" doc:"true"`
-	ErrorString string `json:"errorString" doc:"true"`
+	Query       string                 `json:"query" doc:"true"`
+	ErrorString string                 `json:"errorString" doc:"true"`
+	ErrorDetail *cb_sdk.SDKErrorDetail `json:"errorDetail,omitempty" doc:"true"`
+}
+
+// LockTimings aggregates the timings and contention observed while documents were locked. The CAS of the first
+// MaxLockedDocuments documents left locked is reported in LockedDocuments.
+type LockTimings struct {
+	Locked               int64             `json:"locked" doc:"true"`
+	Unlocked             int64             `json:"unlocked" doc:"true"`
+	ContentionAttempts   int64             `json:"contentionAttempts" doc:"true"`
+	DocumentLockedErrors int64             `json:"documentLockedErrors" doc:"true"`
+	AvgAcquireTimeInMs   float64           `json:"avgAcquireTimeInMs" doc:"true"`
+	MaxAcquireTimeInMs   float64           `json:"maxAcquireTimeInMs" doc:"true"`
+	AvgHoldTimeInMs      float64           `json:"avgHoldTimeInMs" doc:"true"`
+	MaxHoldTimeInMs      float64           `json:"maxHoldTimeInMs" doc:"true"`
+	AvgUnlockTimeInMs    float64           `json:"avgUnlockTimeInMs" doc:"true"`
+	MaxUnlockTimeInMs    float64           `json:"maxUnlockTimeInMs" doc:"true"`
+	LockedDocuments      map[string]uint64 `json:"lockedDocuments,omitempty" doc:"true"`
+}
+
+// QueryShapeResult aggregates the latency, throughput and returned rows of a query. Server reported metrics
+// are only available for N1QL queries.
+type QueryShapeResult struct {
+	Count                int64     `json:"count" doc:"true"`
+	Failures             int64     `json:"failures" doc:"true"`
+	ErrorRate            float64   `json:"errorRate" doc:"true"`
+	QueriesPerSecond     float64   `json:"queriesPerSecond" doc:"true"`
+	TotalRows            uint64    `json:"totalRows" doc:"true"`
+	AvgRows              float64   `json:"avgRows" doc:"true"`
+	AvgLatencyInMs       float64   `json:"avgLatencyInMs" doc:"true"`
+	MaxLatencyInMs       float64   `json:"maxLatencyInMs" doc:"true"`
+	P50LatencyInMs       float64   `json:"p50LatencyInMs" doc:"true"`
+	P95LatencyInMs       float64   `json:"p95LatencyInMs" doc:"true"`
+	P99LatencyInMs       float64   `json:"p99LatencyInMs" doc:"true"`
+	AvgElapsedTimeInMs   float64   `json:"avgElapsedTimeInMs,omitempty" doc:"true"`
+	AvgExecutionTimeInMs float64   `json:"avgExecutionTimeInMs,omitempty" doc:"true"`
+	ResultCount          uint64    `json:"resultCount,omitempty" doc:"true"`
+	AtPlusFallbacks      int64     `json:"atPlusFallbacks,omitempty" doc:"true"`
+	metricsCount         int64     `json:"-"`
+	latencies            []float64 `json:"-"`
+}
+
+// IndexOutcome is the outcome of an index management operation on an index.
+type IndexOutcome struct {
+	Operation    string   `json:"operation" doc:"true"`
+	State        string   `json:"state,omitempty" doc:"true"`
+	IndexKey     []string `json:"indexKey,omitempty" doc:"true"`
+	Condition    string   `json:"condition,omitempty" doc:"true"`
+	Partition    string   `json:"partition,omitempty" doc:"true"`
+	DurationInMs int64    `json:"durationInMs" doc:"true"`
+	Error        string   `json:"error,omitempty" doc:"true"`
+}
+
+// CollectionResult is the result of a bulk operation on a collection as a part of an operation fanned out
+// across collections. The complete result is available against its ResultSeed.
+type CollectionResult struct {
+	ResultSeed int64  `json:"resultSeed" doc:"true"`
+	Success    int64  `json:"success" doc:"true"`
+	Failure    int64  `json:"failure" doc:"true"`
+	ErrorOther string `json:"otherErrors,omitempty" doc:"true"`
+}
+
+// PlanStepResult is the status of a step of a plan along with the result of its task, which is available in
+// full against its ResultSeed.
+type PlanStepResult struct {
+	Operation  string `json:"operation" doc:"true"`
+	Status     string `json:"status" doc:"true"`
+	ResultSeed int64  `json:"resultSeed,omitempty" doc:"true"`
+	Success    int64  `json:"success" doc:"true"`
+	Failure    int64  `json:"failure" doc:"true"`
+	ErrorOther string `json:"otherErrors,omitempty" doc:"true"`
+}
+
+// ScheduledRun is the status of a run of a scheduled task along with its result, which is available in full
+// against its ResultSeed.
+type ScheduledRun struct {
+	StartTime  string `json:"startTime" doc:"true"`
+	Status     string `json:"status" doc:"true"`
+	ResultSeed int64  `json:"resultSeed,omitempty" doc:"true"`
+	Success    int64  `json:"success" doc:"true"`
+	Failure    int64  `json:"failure" doc:"true"`
+	ErrorOther string `json:"otherErrors,omitempty" doc:"true"`
+}
+
+// QueryMismatch holds the rows expected and the rows last returned by a query whose result did not match
+// the documents expected to exist.
+type QueryMismatch struct {
+	Count    int64  `json:"count" doc:"true"`
+	Expected string `json:"expected" doc:"true"`
+	Actual   string `json:"actual" doc:"true"`
+}
+
+// SearchQueryResult aggregates the latency and hit counts of a full text search query shape.
+type SearchQueryResult struct {
+	Count          int64   `json:"count" doc:"true"`
+	Failures       int64   `json:"failures" doc:"true"`
+	TotalHits      uint64  `json:"totalHits" doc:"true"`
+	AvgHits        float64 `json:"avgHits" doc:"true"`
+	AvgLatencyInMs float64 `json:"avgLatencyInMs" doc:"true"`
+	MaxLatencyInMs float64 `json:"maxLatencyInMs" doc:"true"`
+}
+
+// ReadResult aggregates the source and existence of documents read using a read mode.
+type ReadResult struct {
+	ReadMode    string `json:"readMode" doc:"true"`
+	FromActive  int64  `json:"fromActive" doc:"true"`
+	FromReplica int64  `json:"fromReplica" doc:"true"`
+	Exists      int64  `json:"exists" doc:"true"`
+	NotExists   int64  `json:"notExists" doc:"true"`
+	Copies      int64  `json:"copies" doc:"true"`
+}
+
+// DurabilityResult summarises the verification of the replica copies of documents against their active copy. The
+// first MaxUnderReplicatedKeys under replicated documents are reported in UnderReplicatedKeys.
+type DurabilityResult struct {
+	Verified            int64                                 `json:"verified" doc:"true"`
+	FullyReplicated     int64                                 `json:"fullyReplicated" doc:"true"`
+	UnderReplicated     int64                                 `json:"underReplicated" doc:"true"`
+	StaleReplicas       int64                                 `json:"staleReplicas" doc:"true"`
+	ContentMismatch     int64                                 `json:"contentMismatch" doc:"true"`
+	Skipped             int64                                 `json:"skipped" doc:"true"`
+	UnderReplicatedKeys map[string]cb_sdk.ReplicaVerification `json:"underReplicatedKeys,omitempty" doc:"true"`
+}
+
+// CasResult summarises CAS guarded replaces. Attempts counts every replace, including retries, and CasMismatchRate is
+// the fraction of attempts that failed with a CAS mismatch. Documents which did not exist have no CAS to guard with
+// and are inserted instead, Inserted and InsertConflicts count these inserts and the ones failing as the document
+// was created concurrently.
+type CasResult struct {
+	Attempts          int64   `json:"attempts" doc:"true"`
+	Replaced          int64   `json:"replaced" doc:"true"`
+	CasMismatch       int64   `json:"casMismatch" doc:"true"`
+	InjectedConflicts int64   `json:"injectedConflicts" doc:"true"`
+	CasMismatchRate   float64 `json:"casMismatchRate" doc:"true"`
+	Inserted          int64   `json:"inserted" doc:"true"`
+	InsertConflicts   int64   `json:"insertConflicts" doc:"true"`
+}
+
+// ScanResult summarises the items returned by a range or sampling scan.
+type ScanResult struct {
+	ScanType        string  `json:"scanType" doc:"true"`
+	ItemsReturned   int64   `json:"itemsReturned" doc:"true"`
+	ItemsInKeySpace int64   `json:"itemsInKeySpace" doc:"true"`
+	ExpectedItems   int64   `json:"expectedItems" doc:"true"`
+	MissingItems    int64   `json:"missingItems" doc:"true"`
+	UnexpectedItems int64   `json:"unexpectedItems" doc:"true"`
+	DurationInMs    int64   `json:"durationInMs" doc:"true"`
+	ItemsPerSecond  float64 `json:"itemsPerSecond" doc:"true"`
+}
+
+// TransactionResult aggregates the outcomes and timings of transactions.
+type TransactionResult struct {
+	Committed              int64   `json:"committed" doc:"true"`
+	RolledBack             int64   `json:"rolledBack" doc:"true"`
+	Ambiguous              int64   `json:"ambiguous" doc:"true"`
+	Failed                 int64   `json:"failed" doc:"true"`
+	Attempts               int64   `json:"attempts" doc:"true"`
+	AvgAttemptTimeInMs     float64 `json:"avgAttemptTimeInMs" doc:"true"`
+	MaxAttemptTimeInMs     float64 `json:"maxAttemptTimeInMs" doc:"true"`
+	AvgTransactionTimeInMs float64 `json:"avgTransactionTimeInMs" doc:"true"`
+	MaxTransactionTimeInMs float64 `json:"maxTransactionTimeInMs" doc:"true"`
 }
 
 type ResultHelper struct {
@@ -57,19 +252,37 @@ type ResultHelper struct {
 
 // TaskResult defines the type of result stored in a response after an operation.
 type TaskResult struct {
-	ResultSeed    int64                            `json:"resultSeed"`
-	Operation     string                           `json:"operation"`
-	ErrorOther    string                           `json:"otherErrors"`
-	Success       int64                            `json:"success"`
-	Failure       int64                            `json:"failure"`
-	BulkError     map[string][]FailedDocument      `json:"bulkErrors"`
-	RetriedError  map[string][]FailedDocument      `json:"retriedError"`
-	QueryError    map[string][]FailedQuery         `json:"queryErrors"`
-	SingleResult  map[string]SingleOperationResult `json:"singleResult"`
-	ResultChannel chan ResultHelper                `json:"-"`
-	lock          sync.Mutex                       `json:"-"`
-	ctx           context.Context                  `json:"-"`
-	cancel        context.CancelFunc               `json:"-"`
+	ResultSeed      int64                            `json:"resultSeed"`
+	Operation       string                           `json:"operation"`
+	ErrorOther      string                           `json:"otherErrors"`
+	Success         int64                            `json:"success"`
+	Failure         int64                            `json:"failure"`
+	BulkError       map[string][]FailedDocument      `json:"bulkErrors"`
+	RetriedError    map[string][]FailedDocument      `json:"retriedError"`
+	QueryError      map[string][]FailedQuery         `json:"queryErrors"`
+	ErrorClasses    map[string]int64                 `json:"errorClasses,omitempty"`
+	NodeResults     map[string]*NodeResult           `json:"nodeResults,omitempty"`
+	SingleResult    map[string]SingleOperationResult `json:"singleResult"`
+	LockTimings     *LockTimings                     `json:"lockTimings,omitempty"`
+	ReadResult      *ReadResult                      `json:"readResult,omitempty"`
+	ScanResult      *ScanResult                      `json:"scanResult,omitempty"`
+	Durability      *DurabilityResult                `json:"durability,omitempty"`
+	Cas             *CasResult                       `json:"cas,omitempty"`
+	Transactions    *TransactionResult               `json:"transactions,omitempty"`
+	SearchResult    map[string]*SearchQueryResult    `json:"searchResult,omitempty"`
+	QueryResult     map[string]*QueryShapeResult     `json:"queryResult,omitempty"`
+	QueryMismatch   map[string]*QueryMismatch        `json:"queryMismatch,omitempty"`
+	Indexes         map[string]IndexOutcome          `json:"indexes,omitempty"`
+	ManagementError map[string]string                `json:"managementErrors,omitempty"`
+	Collections     map[string]CollectionResult      `json:"collections,omitempty"`
+	Steps           map[string]PlanStepResult        `json:"steps,omitempty"`
+	ScheduledRuns   []ScheduledRun                   `json:"scheduledRuns,omitempty"`
+	ResultChannel   chan ResultHelper                `json:"-"`
+	lock            sync.Mutex                       `json:"-"`
+	ctx             context.Context                  `json:"-"`
+	cancel          context.CancelFunc               `json:"-"`
+	locator         KeyLocator                       `json:"-"`
+	sampleRate      float64                          `json:"-"`
 }
 
 // ConfigTaskResult returns a new instance of TaskResult
@@ -127,10 +340,389 @@ func (t *TaskResult) IncrementQueryFailure(query string, err error) {
 	t.QueryError[v] = append(t.QueryError[v], FailedQuery{
 		Query:       query,
 		ErrorString: errorString,
+		ErrorDetail: t.classifyError(err),
 	})
 	t.lock.Unlock()
 }
 
+// RecordLockTiming saves the timings of a lock cycle on a document. unlockTime is ignored
+// if the document was not unlocked explicitly.
+func (t *TaskResult) RecordLockTiming(acquireTime, holdTime, unlockTime time.Duration, unlocked bool,
+	contentionAttempts, documentLockedErrors int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.LockTimings == nil {
+		t.LockTimings = &LockTimings{}
+	}
+	l := t.LockTimings
+	l.Locked++
+	l.ContentionAttempts += contentionAttempts
+	l.DocumentLockedErrors += documentLockedErrors
+	l.AvgAcquireTimeInMs, l.MaxAcquireTimeInMs = updateTiming(l.AvgAcquireTimeInMs, l.MaxAcquireTimeInMs,
+		acquireTime, l.Locked)
+	l.AvgHoldTimeInMs, l.MaxHoldTimeInMs = updateTiming(l.AvgHoldTimeInMs, l.MaxHoldTimeInMs, holdTime, l.Locked)
+	if unlocked {
+		l.Unlocked++
+		l.AvgUnlockTimeInMs, l.MaxUnlockTimeInMs = updateTiming(l.AvgUnlockTimeInMs, l.MaxUnlockTimeInMs,
+			unlockTime, l.Unlocked)
+	}
+}
+
+// RecordLockedDocument saves the CAS of a document left locked, which is needed to unlock it.
+func (t *TaskResult) RecordLockedDocument(docId string, cas uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.LockTimings == nil {
+		t.LockTimings = &LockTimings{}
+	}
+	if t.LockTimings.LockedDocuments == nil {
+		t.LockTimings.LockedDocuments = make(map[string]uint64)
+	}
+	if len(t.LockTimings.LockedDocuments) < MaxLockedDocuments {
+		t.LockTimings.LockedDocuments[docId] = cas
+	}
+}
+
+// RecordIndexOutcome saves the outcome of an index management operation on an index.
+func (t *TaskResult) RecordIndexOutcome(indexName string, outcome IndexOutcome) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.Indexes == nil {
+		t.Indexes = make(map[string]IndexOutcome)
+	}
+	if outcome.Error != "" {
+		t.Failure++
+	} else {
+		t.Success++
+	}
+	t.Indexes[indexName] = outcome
+}
+
+// RecordManagementOutcome saves the outcome of a management operation on a bucket, scope or collection.
+func (t *TaskResult) RecordManagementOutcome(name string, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if err == nil {
+		t.Success++
+		return
+	}
+	t.Failure++
+	if t.ManagementError == nil {
+		t.ManagementError = make(map[string]string)
+	}
+	t.ManagementError[name] = err.Error()
+}
+
+// RecordCollectionResult saves the result of a bulk operation on a collection and adds it to the overall result.
+func (t *TaskResult) RecordCollectionResult(collection string, result CollectionResult) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.Collections == nil {
+		t.Collections = make(map[string]CollectionResult)
+	}
+	t.Success += result.Success
+	t.Failure += result.Failure
+	t.Collections[collection] = result
+}
+
+// RecordPlanStep saves the status and result of a step of a plan.
+func (t *TaskResult) RecordPlanStep(step string, result PlanStepResult) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.Steps == nil {
+		t.Steps = make(map[string]PlanStepResult)
+	}
+	t.Steps[step] = result
+}
+
+// RecordScheduledRun saves the status of a run of a scheduled task, replacing the status saved for the run
+// previously.
+func (t *TaskResult) RecordScheduledRun(index int, run ScheduledRun) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for len(t.ScheduledRuns) <= index {
+		t.ScheduledRuns = append(t.ScheduledRuns, ScheduledRun{})
+	}
+	t.ScheduledRuns[index] = run
+}
+
+// RecordQueryCheck saves the outcome of comparing the rows returned by a query with the expected rows.
+func (t *TaskResult) RecordQueryCheck(query string, expected string, actual string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if expected == actual {
+		t.Success++
+		return
+	}
+	t.Failure++
+	if t.QueryMismatch == nil {
+		t.QueryMismatch = make(map[string]*QueryMismatch)
+	}
+	m, ok := t.QueryMismatch[query]
+	if !ok {
+		m = &QueryMismatch{Expected: expected}
+		t.QueryMismatch[query] = m
+	}
+	m.Count++
+	m.Actual = actual
+}
+
+// RecordQuery saves the latency and the number of rows returned by a query. Latency and rows of failed
+// queries are not taken into account.
+func (t *TaskResult) RecordQuery(query string, latency time.Duration, rows uint64, failed bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.QueryResult == nil {
+		t.QueryResult = make(map[string]*QueryShapeResult)
+	}
+	r, ok := t.QueryResult[query]
+	if !ok {
+		r = &QueryShapeResult{}
+		t.QueryResult[query] = r
+	}
+	if failed {
+		r.Failures++
+		return
+	}
+	t.Success++
+	r.Count++
+	r.TotalRows += rows
+	r.AvgRows = float64(r.TotalRows) / float64(r.Count)
+	r.AvgLatencyInMs, r.MaxLatencyInMs = updateTiming(r.AvgLatencyInMs, r.MaxLatencyInMs, latency, r.Count)
+
+	// latencies are sampled to a reservoir of MaxLatencySamples for computing percentiles.
+	ms := float64(latency.Microseconds()) / 1000
+	if len(r.latencies) < MaxLatencySamples {
+		r.latencies = append(r.latencies, ms)
+	} else if i := rand.Int63n(r.Count); i < MaxLatencySamples {
+		r.latencies[i] = ms
+	}
+}
+
+// RecordAtPlusFallback saves that a query asking for at_plus scan consistency ran not_bounded as no mutation
+// token was recorded for its collection yet.
+func (t *TaskResult) RecordAtPlusFallback(query string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.QueryResult == nil {
+		t.QueryResult = make(map[string]*QueryShapeResult)
+	}
+	r, ok := t.QueryResult[query]
+	if !ok {
+		r = &QueryShapeResult{}
+		t.QueryResult[query] = r
+	}
+	r.AtPlusFallbacks++
+}
+
+// RecordQueryMetrics saves the metrics reported by the server for a successful N1QL query.
+func (t *TaskResult) RecordQueryMetrics(query string, elapsedTime, executionTime time.Duration, resultCount uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	r, ok := t.QueryResult[query]
+	if !ok {
+		return
+	}
+	r.metricsCount++
+	r.ResultCount += resultCount
+	r.AvgElapsedTimeInMs, _ = updateTiming(r.AvgElapsedTimeInMs, 0, elapsedTime, r.metricsCount)
+	r.AvgExecutionTimeInMs, _ = updateTiming(r.AvgExecutionTimeInMs, 0, executionTime, r.metricsCount)
+}
+
+// SummariseQueryResult computes the error rate, throughput and latency percentiles of every query run for the
+// given duration.
+func (t *TaskResult) SummariseQueryResult(duration time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, r := range t.QueryResult {
+		if total := r.Count + r.Failures; total > 0 {
+			r.ErrorRate = float64(r.Failures) / float64(total)
+		}
+		if duration > 0 {
+			r.QueriesPerSecond = float64(r.Count) / duration.Seconds()
+		}
+		latencies := append([]float64(nil), r.latencies...)
+		sort.Float64s(latencies)
+		r.P50LatencyInMs = percentile(latencies, 50)
+		r.P95LatencyInMs = percentile(latencies, 95)
+		r.P99LatencyInMs = percentile(latencies, 99)
+	}
+}
+
+// percentile returns the p-th percentile of sorted samples using the nearest rank method.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// RecordSearchQuery saves the latency and hit count of a search query against its shape. Latency and hits
+// of failed queries are not taken into account.
+func (t *TaskResult) RecordSearchQuery(shape string, latency time.Duration, hits uint64, failed bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.SearchResult == nil {
+		t.SearchResult = make(map[string]*SearchQueryResult)
+	}
+	r, ok := t.SearchResult[shape]
+	if !ok {
+		r = &SearchQueryResult{}
+		t.SearchResult[shape] = r
+	}
+	if failed {
+		r.Failures++
+		return
+	}
+	t.Success++
+	r.Count++
+	r.TotalHits += hits
+	r.AvgHits = float64(r.TotalHits) / float64(r.Count)
+	r.AvgLatencyInMs, r.MaxLatencyInMs = updateTiming(r.AvgLatencyInMs, r.MaxLatencyInMs, latency, r.Count)
+}
+
+// RecordTransaction saves the outcome of a transaction along with the time taken by each of its attempts.
+func (t *TaskResult) RecordTransaction(outcome string, attemptTimes []time.Duration, totalTime time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.Transactions == nil {
+		t.Transactions = &TransactionResult{}
+	}
+	tr := t.Transactions
+	switch outcome {
+	case TransactionCommitted:
+		tr.Committed++
+	case TransactionRolledBack:
+		tr.RolledBack++
+	case TransactionAmbiguous:
+		tr.Ambiguous++
+	default:
+		tr.Failed++
+	}
+	for _, attemptTime := range attemptTimes {
+		tr.Attempts++
+		tr.AvgAttemptTimeInMs, tr.MaxAttemptTimeInMs = updateTiming(tr.AvgAttemptTimeInMs, tr.MaxAttemptTimeInMs,
+			attemptTime, tr.Attempts)
+	}
+	total := tr.Committed + tr.RolledBack + tr.Ambiguous + tr.Failed
+	tr.AvgTransactionTimeInMs, tr.MaxTransactionTimeInMs = updateTiming(tr.AvgTransactionTimeInMs,
+		tr.MaxTransactionTimeInMs, totalTime, total)
+}
+
+// updateTiming returns the running average and maximum after adding the nth sample.
+func updateTiming(avg, max float64, sample time.Duration, n int64) (float64, float64) {
+	ms := float64(sample.Microseconds()) / 1000
+	avg += (ms - avg) / float64(n)
+	if ms > max {
+		max = ms
+	}
+	return avg, max
+}
+
+// RecordRead saves the source and existence of a document read successfully.
+func (t *TaskResult) RecordRead(r cb_sdk.ReadDocumentResult) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.ReadResult == nil {
+		t.ReadResult = &ReadResult{ReadMode: r.ReadMode}
+	}
+	if r.ReadMode == cb_sdk.ReadModeExists {
+		if r.Exists {
+			t.ReadResult.Exists++
+		} else {
+			t.ReadResult.NotExists++
+		}
+		return
+	}
+	t.ReadResult.Exists++
+	if r.IsReplica {
+		t.ReadResult.FromReplica++
+	} else {
+		t.ReadResult.FromActive++
+	}
+	t.ReadResult.Copies += r.Copies
+}
+
+// RecordCasReplace saves the outcome of a CAS guarded replace and whether a CAS conflict was injected before it.
+func (t *TaskResult) RecordCasReplace(injectedConflict bool, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.Cas == nil {
+		t.Cas = &CasResult{}
+	}
+	t.Cas.Attempts++
+	if injectedConflict {
+		t.Cas.InjectedConflicts++
+	}
+	switch {
+	case err == nil:
+		t.Cas.Replaced++
+	case cb_sdk.IsCasMismatch(err):
+		t.Cas.CasMismatch++
+	}
+	t.Cas.CasMismatchRate = float64(t.Cas.CasMismatch) / float64(t.Cas.Attempts)
+}
+
+// RecordCasInsert saves the outcome of inserting a document which did not exist for a CAS guarded replace.
+func (t *TaskResult) RecordCasInsert(err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.Cas == nil {
+		t.Cas = &CasResult{}
+	}
+	switch {
+	case err == nil:
+		t.Cas.Inserted++
+	case cb_sdk.IsDocumentExists(err):
+		t.Cas.InsertConflicts++
+	}
+}
+
+// RecordDurabilitySkipped saves the number of documents not verified as they are not expected to exist.
+func (t *TaskResult) RecordDurabilitySkipped(skipped int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.Durability == nil {
+		t.Durability = &DurabilityResult{}
+	}
+	t.Durability.Skipped = skipped
+}
+
+// RecordReplicaVerification saves the verification of the replica copies of a document. A document verified again
+// after being reported under replicated, when retrying failures, replaces its previous verification.
+func (t *TaskResult) RecordReplicaVerification(docId string, v cb_sdk.ReplicaVerification) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.Durability == nil {
+		t.Durability = &DurabilityResult{}
+	}
+	if previous, ok := t.Durability.UnderReplicatedKeys[docId]; ok {
+		delete(t.Durability.UnderReplicatedKeys, docId)
+		t.Durability.Verified--
+		t.Durability.UnderReplicated--
+		t.Durability.StaleReplicas -= int64(len(previous.StaleReplicas))
+		t.Durability.ContentMismatch -= int64(previous.ContentMismatch)
+	}
+	t.Durability.Verified++
+	t.Durability.StaleReplicas += int64(len(v.StaleReplicas))
+	t.Durability.ContentMismatch += int64(v.ContentMismatch)
+	if !v.UnderReplicated() {
+		t.Durability.FullyReplicated++
+		return
+	}
+	t.Durability.UnderReplicated++
+	if t.Durability.UnderReplicatedKeys == nil {
+		t.Durability.UnderReplicatedKeys = make(map[string]cb_sdk.ReplicaVerification)
+	}
+	if len(t.Durability.UnderReplicatedKeys) < MaxUnderReplicatedKeys {
+		t.Durability.UnderReplicatedKeys[docId] = v
+	}
+}
+
 // SaveResultIntoFile stores the task result on a file. It returns an error if saving fails.
 func (t *TaskResult) SaveResultIntoFile() error {
 	cwd, err := os.Getwd()
@@ -274,7 +866,7 @@ func (t *TaskResult) StoreResultList(resultList []ResultHelper) {
 	for _, x := range resultList {
 		t.Failure++
 		v, errorString := cb_sdk.CheckSDKException(x.err)
-		t.BulkError[v] = append(t.BulkError[v], FailedDocument{
+		failedDocument := FailedDocument{
 			SDKTiming: SDKTiming{
 				SendTime: x.initTime,
 				AckTime:  time.Now().UTC().Format(time.RFC850),
@@ -284,8 +876,92 @@ func (t *TaskResult) StoreResultList(resultList []ResultHelper) {
 			Cas:         x.cas,
 			ErrorString: errorString,
 			Offset:      x.offset,
-		})
+			ErrorDetail: t.classifyError(x.err),
+		}
+		t.attributeFailure(&failedDocument, x.err)
+		t.BulkError[v] = append(t.BulkError[v], failedDocument)
+	}
+}
+
+// classifyError counts an error by its exception and retry reason in ErrorClasses, and returns its detail if the SDK
+// returned any context with it. It is called with the lock held.
+func (t *TaskResult) classifyError(err error) *cb_sdk.SDKErrorDetail {
+	detail := cb_sdk.GetSDKErrorDetail(err)
+	if t.ErrorClasses == nil {
+		t.ErrorClasses = make(map[string]int64)
+	}
+	t.ErrorClasses[detail.Classification()]++
+	if !detail.HasContext() {
+		return nil
+	}
+	return &detail
+}
+
+// SetKeyLocator sets the KeyLocator used to attribute operations to nodes. Failed operations are always attributed,
+// and successful operations with the probability sampleRate.
+func (t *TaskResult) SetKeyLocator(locator KeyLocator, sampleRate float64) {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	t.locator = locator
+	t.sampleRate = sampleRate
+}
+
+func (t *TaskResult) nodeResult(node string) *NodeResult {
+	if node == "" {
+		node = UnknownNode
+	}
+	if t.NodeResults == nil {
+		t.NodeResults = make(map[string]*NodeResult)
+	}
+	if _, ok := t.NodeResults[node]; !ok {
+		t.NodeResults[node] = &NodeResult{}
+	}
+	return t.NodeResults[node]
+}
+
+// attributeFailure sets the node and vBucket of a failed document and counts the failure in NodeResults. The node is
+// the one the SDK last dispatched the operation to if known, else the node hosting the active vBucket. It is called
+// with the lock held.
+func (t *TaskResult) attributeFailure(failedDocument *FailedDocument, err error) {
+	if t.locator == nil {
+		return
+	}
+	if vBucket, node, errLocate := t.locator.KeyLocation(failedDocument.DocId); errLocate == nil {
+		failedDocument.VBucket = &vBucket
+		failedDocument.Node = node
+	}
+	if failedDocument.ErrorDetail != nil && failedDocument.ErrorDetail.LastDispatchedTo != "" {
+		failedDocument.Node = failedDocument.ErrorDetail.LastDispatchedTo
 	}
+
+	nodeResult := t.nodeResult(failedDocument.Node)
+	nodeResult.Failure++
+	if nodeResult.Errors == nil {
+		nodeResult.Errors = make(map[string]int64)
+	}
+	detail := cb_sdk.GetSDKErrorDetail(err)
+	nodeResult.Errors[detail.Classification()]++
+	if failedDocument.VBucket != nil {
+		if nodeResult.VBuckets == nil {
+			nodeResult.VBuckets = make(map[uint16]int64)
+		}
+		nodeResult.VBuckets[*failedDocument.VBucket]++
+	}
+}
+
+// RecordSuccess attributes a successful operation on a document to the node hosting it, if it is sampled.
+func (t *TaskResult) RecordSuccess(docId string) {
+	t.lock.Lock()
+	locator, sampleRate := t.locator, t.sampleRate
+	t.lock.Unlock()
+	if locator == nil || sampleRate <= 0 || rand.Float64() >= sampleRate {
+		return
+	}
+	_, node, _ := locator.KeyLocation(docId)
+
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	t.nodeResult(node).Success++
 }
 
 func (t *TaskResult) StopStoringResult() {
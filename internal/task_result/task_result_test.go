@@ -0,0 +1,94 @@
+package task_result
+
+import (
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"testing"
+)
+
+type keyLocator map[string]uint16
+
+func (l keyLocator) KeyLocation(docId string) (uint16, string, error) {
+	vBucket, ok := l[docId]
+	if !ok {
+		return 0, "", fmt.Errorf("unknown key %s", docId)
+	}
+	if vBucket == 1023 {
+		return vBucket, "", nil
+	}
+	return vBucket, fmt.Sprintf("10.0.0.%d:11210", vBucket%2+1), nil
+}
+
+func TestNodeAttribution(t *testing.T) {
+	result := &TaskResult{BulkError: make(map[string][]FailedDocument)}
+	result.SetKeyLocator(keyLocator{"a": 10, "b": 11, "c": 1023, "d": 12}, 1)
+
+	timeoutErr := &gocb.TimeoutError{
+		InnerError:       gocb.ErrAmbiguousTimeout,
+		RetryReasons:     []gocb.RetryReason{gocb.KVNotMyVBucketRetryReason},
+		LastDispatchedTo: "10.0.0.2:11210",
+	}
+	result.StoreResultList([]ResultHelper{
+		{docId: "a", err: gocb.ErrDocumentNotFound},
+		{docId: "b", err: gocb.ErrDocumentNotFound},
+		{docId: "d", err: timeoutErr},
+		{docId: "c", err: gocb.ErrTemporaryFailure},
+		{docId: "unknown", err: gocb.ErrTemporaryFailure},
+	})
+	result.RecordSuccess("b")
+
+	node1, node2 := result.NodeResults["10.0.0.1:11210"], result.NodeResults["10.0.0.2:11210"]
+	if node1 == nil || node1.Failure != 1 || node1.VBuckets[10] != 1 {
+		t.Fatalf("unexpected result for node 1 %+v", node1)
+	}
+	if node2 == nil || node2.Failure != 2 || node2.Success != 1 || node2.VBuckets[12] != 1 ||
+		node2.Errors["ambiguous timeout : KV_NOT_MY_VBUCKET"] != 1 {
+		t.Fatalf("unexpected result for node 2 %+v", node2)
+	}
+	if unknown := result.NodeResults[UnknownNode]; unknown == nil || unknown.Failure != 2 || unknown.VBuckets[1023] != 1 {
+		t.Fatalf("unexpected result for unknown node %+v", unknown)
+	}
+
+	for _, failedDocument := range result.BulkError[gocb.ErrAmbiguousTimeout.Error()] {
+		if failedDocument.Node != "10.0.0.2:11210" || failedDocument.VBucket == nil || *failedDocument.VBucket != 12 {
+			t.Fatalf("unexpected attribution %+v", failedDocument)
+		}
+	}
+}
+
+func TestRecordReplicaVerification(t *testing.T) {
+	result := &TaskResult{}
+	result.RecordReplicaVerification("a", cb_sdk.ReplicaVerification{ExpectedReplicas: 2, MatchingReplicas: 2})
+	result.RecordReplicaVerification("b", cb_sdk.ReplicaVerification{ExpectedReplicas: 2, MatchingReplicas: 1,
+		StaleReplicas: []uint64{5}})
+	if d := result.Durability; d.Verified != 2 || d.FullyReplicated != 1 || d.UnderReplicated != 1 ||
+		d.StaleReplicas != 1 || len(d.UnderReplicatedKeys) != 1 {
+		t.Fatalf("unexpected durability result %+v", d)
+	}
+
+	// verifying b again once its replicas caught up replaces its previous verification.
+	result.RecordReplicaVerification("b", cb_sdk.ReplicaVerification{ExpectedReplicas: 2, MatchingReplicas: 2})
+	if d := result.Durability; d.Verified != 2 || d.FullyReplicated != 2 || d.UnderReplicated != 0 ||
+		d.StaleReplicas != 0 || len(d.UnderReplicatedKeys) != 0 {
+		t.Fatalf("unexpected durability result after re-verification %+v", d)
+	}
+}
+
+func TestRecordCasReplace(t *testing.T) {
+	result := &TaskResult{}
+	result.RecordCasReplace(true, fmt.Errorf("%w : replace failed", gocb.ErrCasMismatch))
+	result.RecordCasReplace(false, nil)
+	result.RecordCasReplace(false, gocb.ErrDocumentNotFound)
+	result.RecordCasReplace(false, nil)
+	if c := result.Cas; c.Attempts != 4 || c.Replaced != 2 || c.CasMismatch != 1 || c.InjectedConflicts != 1 ||
+		c.CasMismatchRate != 0.25 {
+		t.Fatalf("unexpected cas result %+v", c)
+	}
+
+	result.RecordCasInsert(nil)
+	result.RecordCasInsert(fmt.Errorf("%w : insert failed", gocb.ErrDocumentExists))
+	if c := result.Cas; c.Inserted != 1 || c.InsertConflicts != 1 || c.Attempts != 4 {
+		t.Fatalf("unexpected cas inserts %+v", c)
+	}
+}
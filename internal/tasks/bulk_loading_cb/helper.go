@@ -2,12 +2,17 @@ package bulk_loading_cb
 
 import (
 	"fmt"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
 	"github.com/couchbaselabs/sirius/internal/docgenerator"
 	"github.com/couchbaselabs/sirius/internal/task_errors"
 	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/task_state"
 	"github.com/couchbaselabs/sirius/internal/tasks"
 	"github.com/jaswdr/faker"
 	"golang.org/x/exp/slices"
+	"math/rand"
+	"strconv"
+	"strings"
 )
 
 // OperationConfig contains all the configuration for document operation.
@@ -24,6 +29,8 @@ type OperationConfig struct {
 	End              int64      `json:"end" doc:"true"`
 	FieldsToChange   []string   `json:"fieldsToChange" doc:"true"`
 	Exceptions       Exceptions `json:"exceptions,omitempty" doc:"true"`
+	// NodeSampleRate is the fraction of successful operations attributed to the node hosting their document.
+	NodeSampleRate float64 `json:"nodeSampleRate,omitempty" doc:"true"`
 }
 
 // ConfigureOperationConfig configures and validate the OperationConfig
@@ -52,7 +59,10 @@ func ConfigureOperationConfig(o *OperationConfig) error {
 		o.End = o.Start
 		return task_errors.ErrMalformedOperationRange
 	}
-	return nil
+	if o.NodeSampleRate < 0 || o.NodeSampleRate > 1 {
+		return fmt.Errorf("%w : nodeSampleRate must be between 0 and 1", task_errors.ErrParsingOperatingConfig)
+	}
+	return o.Exceptions.validate()
 }
 
 // checkBulkWriteOperation is used to check if the Write operation is on main doc or sub doc
@@ -66,7 +76,7 @@ func checkBulkWriteOperation(operation string, subDocFlag bool) bool {
 		}
 	} else {
 		switch operation {
-		case tasks.InsertOperation, tasks.UpsertOperation, tasks.TouchOperation:
+		case tasks.InsertOperation, tasks.UpsertOperation, tasks.TouchOperation, tasks.TransactionOperation:
 			return true
 		default:
 			return false
@@ -79,9 +89,10 @@ func retrieveLastConfig(r *tasks.Request, offset int64, subDocFlag bool) (Operat
 	if r == nil {
 		return OperationConfig{}, task_errors.ErrRequestIsNil
 	}
-	for i := range r.Tasks {
-		if checkBulkWriteOperation(r.Tasks[len(r.Tasks)-i-1].Operation, subDocFlag) {
-			task, ok := r.Tasks[len(r.Tasks)-i-1].Task.(BulkTask)
+	allTasks := r.AllTasks()
+	for i := range allTasks {
+		if checkBulkWriteOperation(allTasks[len(allTasks)-i-1].Operation, subDocFlag) {
+			task, ok := allTasks[len(allTasks)-i-1].Task.(BulkTask)
 			if ok {
 				operationConfig, taskState := task.GetOperationConfig()
 				if operationConfig == nil {
@@ -108,8 +119,9 @@ func retracePreviousFailedInsertions(r *tasks.Request, collectionIdentifier stri
 	defer r.Unlock()
 	r.Lock()
 	result := make(map[int64]struct{})
-	for i := range r.Tasks {
-		td := r.Tasks[i]
+	allTasks := r.AllTasks()
+	for i := range allTasks {
+		td := allTasks[i]
 		if td.Operation == tasks.InsertOperation {
 			if task, ok := td.Task.(BulkTask); ok {
 				u, ok1 := task.(*InsertTask)
@@ -130,7 +142,70 @@ func retracePreviousFailedInsertions(r *tasks.Request, collectionIdentifier stri
 	return result, nil
 }
 
+// ExpectedItemCount returns the number of documents loaded by Sirius which are expected to exist in a collection as
+// per the completed writes of every key space.
+func ExpectedItemCount(r *tasks.Request, collectionIdentifier string) (int64, error) {
+	if r == nil {
+		return 0, task_errors.ErrRequestIsNil
+	}
+	existingOffsets, err := retraceExistingOffsets(r, collectionIdentifier)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for _, offsets := range existingOffsets {
+		count += int64(len(offsets))
+	}
+	return count, nil
+}
+
+// ForEachExpectedDocument calls add with every document expected to exist in a collection as per the completed
+// writes of every key space, one document at a time so that the documents are never held in memory together.
+// Every document is built using the configuration of the last write done on its offset.
+func ForEachExpectedDocument(r *tasks.Request, collectionIdentifier string, add func(document interface{}) error) error {
+	if r == nil {
+		return task_errors.ErrRequestIsNil
+	}
+	collectionMetaData := r.MetaData.GetCollectionMetadata(collectionIdentifier)
+	existingOffsets, err := retraceExistingOffsets(r, collectionIdentifier)
+	if err != nil {
+		return err
+	}
+
+	for _, offsets := range existingOffsets {
+		for offset := range offsets {
+			operationConfig, err := retrieveLastConfig(r, offset, false)
+			if err != nil {
+				continue
+			}
+			gen := docgenerator.Reset(
+				operationConfig.KeySize,
+				operationConfig.DocSize,
+				operationConfig.DocType,
+				operationConfig.KeyPrefix,
+				operationConfig.KeySuffix,
+				operationConfig.TemplateName,
+			)
+			fake := faker.NewWithSeed(rand.NewSource(collectionMetaData.Seed + offset))
+			document, err := gen.Template.GenerateDocument(&fake, operationConfig.DocSize)
+			if err != nil {
+				return err
+			}
+			document, err = retracePreviousMutations(r, collectionIdentifier, offset, document, *gen, &fake, -1)
+			if err != nil {
+				return err
+			}
+			if err := add(document); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // retracePreviousDeletions returns a lookup table representing the offsets which are successfully deleted.
+// Offsets removed by committed transactions and offsets whose insertion was never committed by a transaction are
+// considered deleted as well.
 func retracePreviousDeletions(r *tasks.Request, collectionIdentifier string, resultSeed int64) (map[int64]struct{},
 	error) {
 	if r == nil {
@@ -139,8 +214,9 @@ func retracePreviousDeletions(r *tasks.Request, collectionIdentifier string, res
 	defer r.Unlock()
 	r.Lock()
 	result := make(map[int64]struct{})
-	for i := range r.Tasks {
-		td := r.Tasks[i]
+	allTasks := r.AllTasks()
+	for i := range allTasks {
+		td := allTasks[i]
 		if td.Operation == tasks.DeleteOperation {
 			if task, ok := td.Task.(BulkTask); ok {
 				u, ok1 := task.(*DeleteTask)
@@ -156,6 +232,31 @@ func retracePreviousDeletions(r *tasks.Request, collectionIdentifier string, res
 					}
 				}
 			}
+		} else if td.Operation == tasks.TransactionOperation {
+			if task, ok := td.Task.(BulkTask); ok {
+				u, ok1 := task.(*TransactionTask)
+				if ok1 {
+					if collectionIdentifier != u.CollectionIdentifier() {
+						continue
+					}
+					if resultSeed != u.ResultSeed && u.State != nil {
+						completedOffSet := u.State.ReturnCompletedOffset()
+						for offset := u.OperationConfig.Start; offset < u.OperationConfig.End; offset++ {
+							_, committed := completedOffSet[offset]
+							switch u.operationForOffset(offset) {
+							case transactionRemove:
+								if committed {
+									result[offset] = struct{}{}
+								}
+							case transactionInsert:
+								if !committed {
+									result[offset] = struct{}{}
+								}
+							}
+						}
+					}
+				}
+			}
 		}
 	}
 	return result, nil
@@ -173,8 +274,9 @@ func retracePreviousSubDocDeletions(r *tasks.Request, collectionIdentifier strin
 	if r == nil {
 		return result, task_errors.ErrRequestIsNil
 	}
-	for i := range r.Tasks {
-		td := r.Tasks[i]
+	allTasks := r.AllTasks()
+	for i := range allTasks {
+		td := allTasks[i]
 		if td.Operation == tasks.SubDocDeleteOperation {
 			if task, ok := td.Task.(BulkTask); ok {
 				u, ok1 := task.(*SubDocDelete)
@@ -203,8 +305,9 @@ func retracePreviousMutations(r *tasks.Request, collectionIdentifier string, off
 	}
 	defer r.Unlock()
 	r.Lock()
-	for i := range r.Tasks {
-		td := r.Tasks[i]
+	allTasks := r.AllTasks()
+	for i := range allTasks {
+		td := allTasks[i]
 		if td.Operation == tasks.UpsertOperation {
 
 			if tempX, ok := td.Task.(BulkTask); ok {
@@ -230,6 +333,27 @@ func retracePreviousMutations(r *tasks.Request, collectionIdentifier string, off
 				}
 			}
 
+		} else if td.Operation == tasks.TransactionOperation {
+			if tempX, ok := td.Task.(BulkTask); ok {
+				u, ok := tempX.(*TransactionTask)
+				if ok {
+					if collectionIdentifier != u.CollectionIdentifier() {
+						continue
+					}
+					if offset >= (u.OperationConfig.Start) && (offset < u.OperationConfig.End) && resultSeed != u.
+						ResultSeed {
+						if u.State == nil {
+							return doc, fmt.Errorf("Unable to retrace previous mutations on sirius for " + u.CollectionIdentifier())
+						}
+						// only replaces of committed transactions mutate the document.
+						if _, ok := u.State.ReturnCompletedOffset()[offset]; ok &&
+							u.operationForOffset(offset) == transactionReplace {
+							doc, _ = gen.Template.UpdateDocument(u.OperationConfig.FieldsToChange, doc,
+								u.OperationConfig.DocSize, fake)
+						}
+					}
+				}
+			}
 		}
 	}
 	return doc, nil
@@ -245,8 +369,9 @@ func retracePreviousSubDocMutations(r *tasks.Request, collectionIdentifier strin
 	defer r.Unlock()
 	r.Lock()
 	var result map[string]any = subDocumentMap
-	for i := range r.Tasks {
-		td := r.Tasks[i]
+	allTasks := r.AllTasks()
+	for i := range allTasks {
+		td := allTasks[i]
 		if td.Operation == tasks.SubDocUpsertOperation {
 			if task, ok := td.Task.(BulkTask); ok {
 				u, ok1 := task.(*SubDocUpsert)
@@ -278,8 +403,9 @@ func countMutation(r *tasks.Request, collectionIdentifier string, offset int64,
 	defer r.Unlock()
 	r.Lock()
 	var result int = 0
-	for i := range r.Tasks {
-		td := r.Tasks[i]
+	allTasks := r.AllTasks()
+	for i := range allTasks {
+		td := allTasks[i]
 		if td.Operation == tasks.SubDocUpsertOperation {
 			if task, ok := td.Task.(BulkTask); ok {
 				u, ok1 := task.(*SubDocUpsert)
@@ -350,6 +476,202 @@ func countMutation(r *tasks.Request, collectionIdentifier string, offset int64,
 
 }
 
+// sameKeySpace returns true if both the OperationConfig build the same document keys for an offset.
+func sameKeySpace(o1, o2 *OperationConfig) bool {
+	return o1.KeyPrefix == o2.KeyPrefix && o1.KeySuffix == o2.KeySuffix && o1.KeySize == o2.KeySize
+}
+
+// keySpaceKey identifies the key space of an OperationConfig, two OperationConfig in the same key space as per
+// sameKeySpace having the same keySpaceKey.
+func keySpaceKey(o *OperationConfig) string {
+	return fmt.Sprintf("%s:%s:%d", o.KeyPrefix, o.KeySuffix, o.KeySize)
+}
+
+// retraceExistingOffsets returns, for every key space of a collection keyed by keySpaceKey, a lookup table of the
+// offsets expected to exist as per the completed writes. Offsets are added when inserted, upserted or inserted by a
+// committed transaction and removed when deleted or removed by a committed transaction, in the order of the tasks.
+// Offsets whose write failed and offsets of other key spaces are not part of a key space.
+func retraceExistingOffsets(r *tasks.Request, collectionIdentifier string) (map[string]map[int64]struct{}, error) {
+	if r == nil {
+		return map[string]map[int64]struct{}{}, task_errors.ErrRequestIsNil
+	}
+	defer r.Unlock()
+	r.Lock()
+	result := make(map[string]map[int64]struct{})
+	keySpace := func(o *OperationConfig) map[int64]struct{} {
+		key := keySpaceKey(o)
+		if _, ok := result[key]; !ok {
+			result[key] = make(map[int64]struct{})
+		}
+		return result[key]
+	}
+	allTasks := r.AllTasks()
+	for i := range allTasks {
+		task, ok := allTasks[i].Task.(BulkTask)
+		if !ok || task.CollectionIdentifier() != collectionIdentifier {
+			continue
+		}
+		operationConfig, taskState := task.GetOperationConfig()
+		if operationConfig == nil || taskState == nil {
+			continue
+		}
+		switch u := task.(type) {
+		case *InsertTask, *UpsertTask:
+			offsets := keySpace(operationConfig)
+			for offset := range taskState.ReturnCompletedOffset() {
+				offsets[offset] = struct{}{}
+			}
+		case *DeleteTask:
+			offsets := keySpace(operationConfig)
+			for offset := range taskState.ReturnCompletedOffset() {
+				delete(offsets, offset)
+			}
+		case *TransactionTask:
+			offsets := keySpace(operationConfig)
+			for offset := range taskState.ReturnCompletedOffset() {
+				switch u.operationForOffset(offset) {
+				case transactionInsert:
+					offsets[offset] = struct{}{}
+				case transactionRemove:
+					delete(offsets, offset)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// retraceCounterKeySpaces returns an OperationConfig for every distinct key space on which increment or decrement
+// was performed. The Start and End of each OperationConfig cover all the offsets operated upon.
+func retraceCounterKeySpaces(r *tasks.Request, collectionIdentifier string) ([]OperationConfig, error) {
+	if r == nil {
+		return []OperationConfig{}, task_errors.ErrRequestIsNil
+	}
+	defer r.Unlock()
+	r.Lock()
+	var result []OperationConfig
+	allTasks := r.AllTasks()
+	for i := range allTasks {
+		var operationConfig *OperationConfig
+		switch u := allTasks[i].Task.(type) {
+		case *IncrementTask:
+			if collectionIdentifier != u.CollectionIdentifier() {
+				continue
+			}
+			operationConfig = u.OperationConfig
+		case *DecrementTask:
+			if collectionIdentifier != u.CollectionIdentifier() {
+				continue
+			}
+			operationConfig = u.OperationConfig
+		default:
+			continue
+		}
+		if operationConfig == nil {
+			continue
+		}
+		index := slices.IndexFunc(result, func(o OperationConfig) bool {
+			return sameKeySpace(&o, operationConfig)
+		})
+		if index == -1 {
+			result = append(result, *operationConfig)
+			continue
+		}
+		if operationConfig.Start < result[index].Start {
+			result[index].Start = operationConfig.Start
+		}
+		if operationConfig.End > result[index].End {
+			result[index].End = operationConfig.End
+		}
+	}
+	return result, nil
+}
+
+// retracePreviousCounterMutations returns the expected value of a counter by replaying every successful increment,
+// decrement and deletion performed on the offset in the key space. It returns false if the counter is not expected
+// to exist.
+func retracePreviousCounterMutations(r *tasks.Request, collectionIdentifier string, offset int64,
+	keySpace *OperationConfig, resultSeed int64) (uint64, bool, error) {
+	if r == nil {
+		return 0, false, task_errors.ErrRequestIsNil
+	}
+	defer r.Unlock()
+	r.Lock()
+	var value uint64
+	exists := false
+	allTasks := r.AllTasks()
+	for i := range allTasks {
+		var operationConfig *OperationConfig
+		var counterOptions *cb_sdk.CounterOptions
+		var state *task_state.TaskState
+		var taskResultSeed int64
+		var identifier string
+
+		switch u := allTasks[i].Task.(type) {
+		case *IncrementTask:
+			operationConfig, counterOptions, state = u.OperationConfig, u.CounterOptions, u.State
+			taskResultSeed, identifier = u.ResultSeed, u.CollectionIdentifier()
+		case *DecrementTask:
+			operationConfig, counterOptions, state = u.OperationConfig, u.CounterOptions, u.State
+			taskResultSeed, identifier = u.ResultSeed, u.CollectionIdentifier()
+		case *DeleteTask:
+			operationConfig, state = u.OperationConfig, u.State
+			taskResultSeed, identifier = u.ResultSeed, u.CollectionIdentifier()
+		default:
+			continue
+		}
+
+		if collectionIdentifier != identifier || resultSeed == taskResultSeed || operationConfig == nil ||
+			state == nil || !sameKeySpace(operationConfig, keySpace) {
+			continue
+		}
+		if offset < operationConfig.Start || offset >= operationConfig.End {
+			continue
+		}
+		if _, ok := state.ReturnCompletedOffset()[offset]; !ok {
+			continue
+		}
+
+		if counterOptions == nil {
+			value, exists = 0, false
+			continue
+		}
+		if !exists {
+			if counterOptions.Initial < 0 {
+				return 0, false, fmt.Errorf("unable to retrace counter created outside sirius at offset %d", offset)
+			}
+			value, exists = uint64(counterOptions.Initial), true
+			continue
+		}
+		if allTasks[i].Operation == tasks.IncrementOperation {
+			value += counterOptions.Delta
+		} else if value < counterOptions.Delta {
+			value = 0
+		} else {
+			value -= counterOptions.Delta
+		}
+	}
+	return value, exists, nil
+}
+
+// offsetFromKey returns the offset of a key built by the generator from the seed. It returns false if the key
+// doesn't belong to the generator's key space.
+func offsetFromKey(gen *docgenerator.Generator, seed int64, docId string) (int64, bool) {
+	if !strings.HasPrefix(docId, gen.KeyPrefix) {
+		return 0, false
+	}
+	digits := strings.TrimPrefix(docId, gen.KeyPrefix)
+	i := 0
+	for i < len(digits) && digits[i] >= '0' && digits[i] <= '9' {
+		i++
+	}
+	key, err := strconv.ParseInt(digits[:i], 10, 64)
+	if err != nil || gen.BuildKey(key) != docId {
+		return 0, false
+	}
+	return key - seed, true
+}
+
 // shiftErrToCompletedOnRetrying will bring the offset which successfully completed their respective operation on
 // retrying
 func shiftErrToCompletedOnRetrying(exception string, result *task_result.TaskResult,
@@ -438,10 +760,14 @@ type RetriedResult struct {
 	AckTime  string `json:"ackTime" doc:"true"`
 }
 
+// Exceptions configures the exceptions ignored and retried by a task. ExceptionRetries overrides the retry attempts
+// and retry policy for specific exceptions.
 type Exceptions struct {
-	IgnoreExceptions []string `json:"ignoreExceptions,omitempty" doc:"true"`
-	RetryExceptions  []string `json:"retryExceptions,omitempty" doc:"true"`
-	RetryAttempts    int      `json:"retryAttempts,omitempty" doc:"true"`
+	IgnoreExceptions []string                  `json:"ignoreExceptions,omitempty" doc:"true"`
+	RetryExceptions  []string                  `json:"retryExceptions,omitempty" doc:"true"`
+	RetryAttempts    int                       `json:"retryAttempts,omitempty" doc:"true"`
+	RetryPolicy      RetryPolicy               `json:"retryPolicy,omitempty" doc:"true"`
+	ExceptionRetries map[string]ExceptionRetry `json:"exceptionRetries,omitempty" doc:"true"`
 }
 
 func GetExceptions(result *task_result.TaskResult, RetryExceptions []string) []string {
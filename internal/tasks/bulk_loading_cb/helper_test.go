@@ -0,0 +1,142 @@
+package bulk_loading_cb
+
+import (
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/docgenerator"
+	"github.com/couchbaselabs/sirius/internal/task_state"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/couchbaselabs/sirius/internal/template"
+	"testing"
+)
+
+func TestRetracePreviousCounterMutations(t *testing.T) {
+	clusterConfig := &cb_sdk.ClusterConfig{ConnectionString: "couchbase://127.0.0.1"}
+	keySpace := &OperationConfig{KeyPrefix: "counter_", Start: 0, End: 2}
+
+	completedState := func(offsets ...int64) *task_state.TaskState {
+		return &task_state.TaskState{KeyStates: task_state.KeyStates{Completed: offsets}}
+	}
+
+	r := tasks.NewRequest("test")
+	r.Tasks = []tasks.TaskWithIdentifier{
+		{Operation: tasks.IncrementOperation, Task: &IncrementTask{
+			ClusterConfig:   clusterConfig,
+			CounterOptions:  &cb_sdk.CounterOptions{Initial: 10, Delta: 5},
+			OperationConfig: keySpace,
+			ResultSeed:      1,
+			State:           completedState(0, 1),
+		}},
+		{Operation: tasks.IncrementOperation, Task: &IncrementTask{
+			ClusterConfig:   clusterConfig,
+			CounterOptions:  &cb_sdk.CounterOptions{Initial: 10, Delta: 5},
+			OperationConfig: keySpace,
+			ResultSeed:      2,
+			State:           completedState(0),
+		}},
+		{Operation: tasks.DecrementOperation, Task: &DecrementTask{
+			ClusterConfig:   clusterConfig,
+			CounterOptions:  &cb_sdk.CounterOptions{Initial: 10, Delta: 20},
+			OperationConfig: keySpace,
+			ResultSeed:      3,
+			State:           completedState(1),
+		}},
+	}
+	collectionIdentifier := r.Tasks[0].Task.(*IncrementTask).CollectionIdentifier()
+
+	// offset 0 is created with initial value and incremented once.
+	value, exists, err := retracePreviousCounterMutations(r, collectionIdentifier, 0, keySpace, 0)
+	if err != nil || !exists || value != 15 {
+		t.Fatalf("offset 0 : expected 15, got %d %v %v", value, exists, err)
+	}
+
+	// offset 1 is created with initial value and decremented below zero.
+	value, exists, err = retracePreviousCounterMutations(r, collectionIdentifier, 1, keySpace, 0)
+	if err != nil || !exists || value != 0 {
+		t.Fatalf("offset 1 : expected 0, got %d %v %v", value, exists, err)
+	}
+
+	// counters in a different key space are not retraced.
+	_, exists, err = retracePreviousCounterMutations(r, collectionIdentifier, 0, &OperationConfig{KeyPrefix: "x"}, 0)
+	if err != nil || exists {
+		t.Fatalf("unexpected counter in different key space")
+	}
+
+	keySpaces, err := retraceCounterKeySpaces(r, collectionIdentifier)
+	if err != nil || len(keySpaces) != 1 || keySpaces[0].End != 2 {
+		t.Fatalf("expected a single key space, got %v %v", keySpaces, err)
+	}
+}
+
+func TestOffsetFromKey(t *testing.T) {
+	gen := docgenerator.ConfigGenerator(20, 0, docgenerator.JsonDocument, "user_", "_x", template.Template(nil))
+	seed := int64(1000)
+
+	if offset, ok := offsetFromKey(gen, seed, gen.BuildKey(seed+42)); !ok || offset != 42 {
+		t.Fatalf("expected offset 42, got %d %v", offset, ok)
+	}
+	for _, docId := range []string{"user_1042", "order_1042_xaaaaaaaaaa", "user__xaaaaaaaaaaaaaa"} {
+		if _, ok := offsetFromKey(gen, seed, docId); ok {
+			t.Fatalf("%s should not belong to the key space", docId)
+		}
+	}
+}
+
+func TestRetracePreviousDeletionsWithTransactions(t *testing.T) {
+	transactionTask := &TransactionTask{
+		ClusterConfig:      &cb_sdk.ClusterConfig{ConnectionString: "couchbase://127.0.0.1"},
+		TransactionOptions: &cb_sdk.TransactionOptions{RemoveRatio: 1},
+		OperationConfig:    &OperationConfig{Start: 0, End: 4},
+		ResultSeed:         1,
+		InsertFrom:         2,
+		State:              &task_state.TaskState{KeyStates: task_state.KeyStates{Completed: []int64{0, 2}}},
+	}
+	r := tasks.NewRequest("test")
+	r.Tasks = []tasks.TaskWithIdentifier{{Operation: tasks.TransactionOperation, Task: transactionTask}}
+
+	deleted, err := retracePreviousDeletions(r, transactionTask.CollectionIdentifier(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 0 is removed by a committed transaction and 3 is never inserted as its transaction didn't commit.
+	for offset, expected := range map[int64]bool{0: true, 1: false, 2: false, 3: true} {
+		if _, ok := deleted[offset]; ok != expected {
+			t.Fatalf("offset %d : expected deleted to be %v", offset, expected)
+		}
+	}
+}
+
+func TestRetraceExistingOffsets(t *testing.T) {
+	clusterConfig := &cb_sdk.ClusterConfig{ConnectionString: "couchbase://127.0.0.1"}
+	keySpace := &OperationConfig{KeyPrefix: "user_", Start: 0, End: 4}
+	counterKeySpace := &OperationConfig{KeyPrefix: "counter_", Start: 4, End: 6}
+	state := func(completed ...int64) *task_state.TaskState {
+		return &task_state.TaskState{KeyStates: task_state.KeyStates{Completed: completed}}
+	}
+
+	r := tasks.NewRequest("test")
+	r.Tasks = []tasks.TaskWithIdentifier{
+		{Operation: tasks.InsertOperation, Task: &InsertTask{ClusterConfig: clusterConfig,
+			OperationConfig: keySpace, State: state(0, 1, 3)}},
+		{Operation: tasks.IncrementOperation, Task: &IncrementTask{ClusterConfig: clusterConfig,
+			OperationConfig: counterKeySpace, State: state(4, 5)}},
+		{Operation: tasks.DeleteOperation, Task: &DeleteTask{ClusterConfig: clusterConfig,
+			OperationConfig: keySpace, State: state(1)}},
+		{Operation: tasks.UpsertOperation, Task: &UpsertTask{ClusterConfig: clusterConfig,
+			OperationConfig: keySpace, State: state(1)}},
+	}
+
+	existing, err := retraceExistingOffsets(r, (&InsertTask{ClusterConfig: clusterConfig}).CollectionIdentifier())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2 failed to insert, 1 is upserted after being deleted and counters are not documents of the key space.
+	offsets := existing[keySpaceKey(keySpace)]
+	for offset, expected := range map[int64]bool{0: true, 1: true, 2: false, 3: true, 4: false, 5: false} {
+		if _, ok := offsets[offset]; ok != expected {
+			t.Fatalf("offset %d : expected existing to be %v", offset, expected)
+		}
+	}
+	if len(existing) != 1 {
+		t.Fatalf("expected a single key space, got %v", existing)
+	}
+}
@@ -0,0 +1,135 @@
+package bulk_loading_cb
+
+import (
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy configures the wait between retries of a failed operation. The first retry waits initialBackoff
+// milliseconds, multiplied by backoffMultiplier on every retry up to maxBackoff milliseconds. Jitter, between 0 and
+// 1, randomly shortens every wait by up to that fraction. Retries stop once maxElapsedTime seconds have elapsed
+// since the first attempt. An empty policy retries immediately.
+type RetryPolicy struct {
+	InitialBackoff    int     `json:"initialBackoff,omitempty" doc:"true"`
+	MaxBackoff        int     `json:"maxBackoff,omitempty" doc:"true"`
+	BackoffMultiplier float64 `json:"backoffMultiplier,omitempty" doc:"true"`
+	Jitter            float64 `json:"jitter,omitempty" doc:"true"`
+	MaxElapsedTime    int     `json:"maxElapsedTime,omitempty" doc:"true"`
+}
+
+// ExceptionRetry overrides the retry attempts and retry policy of Exceptions for operations failing with a specific
+// exception.
+type ExceptionRetry struct {
+	RetryAttempts int         `json:"retryAttempts,omitempty" doc:"true"`
+	RetryPolicy   RetryPolicy `json:"retryPolicy,omitempty" doc:"true"`
+}
+
+func (p *RetryPolicy) validate() error {
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 || p.BackoffMultiplier < 0 || p.MaxElapsedTime < 0 {
+		return fmt.Errorf("%w : backoff and elapsed time cannot be negative", task_errors.ErrInvalidRetryPolicy)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("%w : jitter must be between 0 and 1", task_errors.ErrInvalidRetryPolicy)
+	}
+	if p.MaxBackoff > 0 && p.InitialBackoff > p.MaxBackoff {
+		return fmt.Errorf("%w : initialBackoff is greater than maxBackoff", task_errors.ErrInvalidRetryPolicy)
+	}
+	return nil
+}
+
+// backoff returns the wait before a retry, retry being 1 for the first retry.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	if p.InitialBackoff == 0 {
+		return 0
+	}
+	multiplier := p.BackoffMultiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && (backoff > float64(p.MaxBackoff) || math.IsInf(backoff, 0)) {
+		backoff = float64(p.MaxBackoff)
+	}
+	backoff -= backoff * p.Jitter * rand.Float64()
+	return time.Duration(backoff * float64(time.Millisecond))
+}
+
+// wait waits before a retry and returns false if the attempts are exhausted or the retry would start after the max
+// elapsed time.
+func (p *RetryPolicy) wait(retry, attempts int, start time.Time) bool {
+	if retry >= attempts {
+		return false
+	}
+	backoff := p.backoff(retry)
+	if p.MaxElapsedTime > 0 && time.Since(start)+backoff > time.Duration(p.MaxElapsedTime)*time.Second {
+		return false
+	}
+	time.Sleep(backoff)
+	return true
+}
+
+func (e *Exceptions) validate() error {
+	if e.RetryAttempts < 0 {
+		return fmt.Errorf("%w : retryAttempts cannot be negative", task_errors.ErrInvalidRetryPolicy)
+	}
+	if err := e.RetryPolicy.validate(); err != nil {
+		return err
+	}
+	for exception, override := range e.ExceptionRetries {
+		if override.RetryAttempts < 0 {
+			return fmt.Errorf("%w : retryAttempts of %s cannot be negative", task_errors.ErrInvalidRetryPolicy,
+				exception)
+		}
+		if err := override.RetryPolicy.validate(); err != nil {
+			return fmt.Errorf("%w : %s", err, exception)
+		}
+	}
+	return nil
+}
+
+// retryPolicy returns the retry attempts and retry policy for an operation failing with err.
+func (e *Exceptions) retryPolicy(err error) (int, *RetryPolicy) {
+	if err != nil && len(e.ExceptionRetries) > 0 {
+		exception, _ := cb_sdk.CheckSDKException(err)
+		if override, ok := e.ExceptionRetries[exception]; ok {
+			return override.RetryAttempts, &override.RetryPolicy
+		}
+	}
+	return e.RetryAttempts, &e.RetryPolicy
+}
+
+// Attempt returns true if an operation of a task should be attempted, retry being the number of previous attempts
+// and err the error of the last one. An operation is attempted at least once and up to RetryAttempts times, waiting
+// as per the retry policy before every retry.
+func (e *Exceptions) Attempt(retry int, err error, start time.Time) bool {
+	if retry == 0 {
+		return true
+	}
+	retryAttempts, policy := e.retryPolicy(err)
+	return policy.wait(retry, int(math.Max(float64(1), float64(retryAttempts))), start)
+}
+
+// RetryAttempt returns true if a failed operation being retried after a task should be attempted again. It is
+// attempted up to RetryAttempts + 1 times, waiting as per the retry policy before every retry.
+func (e *Exceptions) RetryAttempt(retry int, err error, start time.Time) bool {
+	if retry == 0 {
+		return true
+	}
+	retryAttempts, policy := e.retryPolicy(err)
+	return policy.wait(retry, retryAttempts+1, start)
+}
+
+// documentExists reads back a document after an ambiguous mutation, whose outcome is unknown, to decide if it was
+// applied before retrying it.
+func documentExists(collection *gocb.Collection, docId string) (bool, error) {
+	result, err := collection.Exists(docId, &gocb.ExistsOptions{Timeout: 5 * time.Second})
+	if err != nil {
+		return false, err
+	}
+	return result.Exists(), nil
+}
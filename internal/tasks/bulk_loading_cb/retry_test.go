@@ -0,0 +1,72 @@
+package bulk_loading_cb
+
+import (
+	"errors"
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: 10, MaxBackoff: 50}
+	for retry, expected := range map[int]time.Duration{1: 10, 2: 20, 3: 40, 4: 50, 100: 50} {
+		if backoff := p.backoff(retry); backoff != expected*time.Millisecond {
+			t.Fatalf("retry %d : expected %v, got %v", retry, expected*time.Millisecond, backoff)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if backoff := p.backoff(2); backoff < 10*time.Millisecond || backoff > 20*time.Millisecond {
+			t.Fatalf("jittered backoff %v out of range", backoff)
+		}
+	}
+
+	if backoff := (&RetryPolicy{}).backoff(3); backoff != 0 {
+		t.Fatalf("expected immediate retry, got %v", backoff)
+	}
+}
+
+func TestExceptionsAttempt(t *testing.T) {
+	attempts := func(err error, attempt func(int, error, time.Time) bool) int {
+		retry, start := 0, time.Now()
+		for ; attempt(retry, err, start); retry++ {
+		}
+		return retry
+	}
+
+	e := &Exceptions{RetryAttempts: 3}
+	if n := attempts(gocb.ErrTimeout, e.Attempt); n != 3 {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+	if n := attempts(gocb.ErrTimeout, e.RetryAttempt); n != 4 {
+		t.Fatalf("expected 4 retry attempts, got %d", n)
+	}
+	if n := attempts(gocb.ErrTimeout, (&Exceptions{}).Attempt); n != 1 {
+		t.Fatalf("expected a single attempt, got %d", n)
+	}
+
+	e.ExceptionRetries = map[string]ExceptionRetry{gocb.ErrTemporaryFailure.Error(): {RetryAttempts: 6}}
+	err := fmt.Errorf("%w | context", gocb.ErrTemporaryFailure)
+	if n := attempts(err, e.Attempt); n != 6 {
+		t.Fatalf("expected 6 attempts for overridden exception, got %d", n)
+	}
+
+	e = &Exceptions{RetryAttempts: 100, RetryPolicy: RetryPolicy{InitialBackoff: 400, MaxElapsedTime: 1}}
+	if n := attempts(gocb.ErrTimeout, e.Attempt); n != 2 {
+		t.Fatalf("expected attempts to stop at max elapsed time, got %d", n)
+	}
+
+	for _, invalid := range []*Exceptions{
+		{RetryAttempts: -1},
+		{RetryPolicy: RetryPolicy{Jitter: 1.5}},
+		{RetryPolicy: RetryPolicy{InitialBackoff: 100, MaxBackoff: 10}},
+		{ExceptionRetries: map[string]ExceptionRetry{"timeout": {RetryPolicy: RetryPolicy{MaxElapsedTime: -1}}}},
+	} {
+		if err := invalid.validate(); !errors.Is(err, task_errors.ErrInvalidRetryPolicy) {
+			t.Fatalf("expected invalid retry policy, got %v", err)
+		}
+	}
+}
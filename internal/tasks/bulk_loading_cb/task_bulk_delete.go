@@ -14,7 +14,6 @@ import (
 	"github.com/couchbaselabs/sirius/internal/template"
 	"golang.org/x/sync/errgroup"
 	"log"
-	"math"
 	"strings"
 	"sync"
 	"time"
@@ -148,6 +147,7 @@ func (task *DeleteTask) Do() error {
 		return task.TearUp()
 	}
 
+	task.Result.SetKeyLocator(collectionObject, task.OperationConfig.NodeSampleRate)
 	deleteDocuments(task, collectionObject)
 	task.Result.Success = task.OperationConfig.End - task.OperationConfig.Start - task.Result.Failure
 
@@ -194,10 +194,10 @@ func deleteDocuments(task *DeleteTask, collectionObject *cb_sdk.CollectionObject
 
 			var err error
 			initTime := time.Now().UTC().Format(time.RFC850)
-			for retry := 0; retry < int(math.Max(float64(1), float64(task.OperationConfig.Exceptions.
-				RetryAttempts))); retry++ {
+			for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.Attempt(retry, err, start); retry++ {
 				initTime = time.Now().UTC().Format(time.RFC850)
-				_, err = collectionObject.Collection.Remove(docId, &gocb.RemoveOptions{
+				var result *gocb.MutationResult
+				result, err = collectionObject.Collection.Remove(docId, &gocb.RemoveOptions{
 					Cas:             gocb.Cas(task.RemoveOptions.Cas),
 					PersistTo:       task.RemoveOptions.PersistTo,
 					ReplicateTo:     task.RemoveOptions.ReplicateTo,
@@ -205,8 +205,15 @@ func deleteDocuments(task *DeleteTask, collectionObject *cb_sdk.CollectionObject
 					Timeout:         time.Duration(task.RemoveOptions.Timeout) * time.Second,
 				})
 				if err == nil {
+					collectionObject.RecordMutation(result)
 					break
 				}
+				if cb_sdk.IsAmbiguousError(err) {
+					if exists, errExists := documentExists(collectionObject.Collection, docId); errExists == nil && !exists {
+						err = nil
+						break
+					}
+				}
 			}
 			if err != nil {
 				if errors.Is(err, gocb.ErrDocumentNotFound) && task.rerun {
@@ -221,6 +228,7 @@ func deleteDocuments(task *DeleteTask, collectionObject *cb_sdk.CollectionObject
 				}
 			}
 
+			task.Result.RecordSuccess(docId)
 			task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
 			<-routineLimiter
 			return nil
@@ -282,7 +290,7 @@ func (task *DeleteTask) PostTaskExceptionHandling(collectionObject *cb_sdk.Colle
 					result := &gocb.MutationResult{}
 
 					initTime := time.Now().UTC().Format(time.RFC850)
-					for retry = 0; retry <= task.OperationConfig.Exceptions.RetryAttempts; retry++ {
+					for start := time.Now(); task.OperationConfig.Exceptions.RetryAttempt(retry, err, start); retry++ {
 						result, err = collectionObject.Collection.Remove(docId, &gocb.RemoveOptions{
 							Cas:             gocb.Cas(task.RemoveOptions.Cas),
 							PersistTo:       task.RemoveOptions.PersistTo,
@@ -292,8 +300,15 @@ func (task *DeleteTask) PostTaskExceptionHandling(collectionObject *cb_sdk.Colle
 						})
 
 						if err == nil {
+							collectionObject.RecordMutation(result)
 							break
 						}
+						if cb_sdk.IsAmbiguousError(err) {
+							if exists, errExists := documentExists(collectionObject.Collection, docId); errExists == nil && !exists {
+								err = gocb.ErrDocumentNotFound
+								break
+							}
+						}
 					}
 
 					if err != nil {
@@ -15,7 +15,6 @@ import (
 	"github.com/jaswdr/faker"
 	"golang.org/x/sync/errgroup"
 	"log"
-	"math"
 	"math/rand"
 	"strings"
 	"sync"
@@ -160,6 +159,7 @@ func (task *InsertTask) Do() error {
 		return task.TearUp()
 	}
 
+	task.Result.SetKeyLocator(collectionObject, task.OperationConfig.NodeSampleRate)
 	insertDocuments(task, collectionObject)
 	task.Result.Success = task.OperationConfig.End - task.OperationConfig.Start - task.Result.Failure
 
@@ -213,10 +213,10 @@ func insertDocuments(task *InsertTask, collectionObject *cb_sdk.CollectionObject
 				return err
 			}
 
-			for retry := 0; retry < int(math.Max(float64(1), float64(task.OperationConfig.Exceptions.
-				RetryAttempts))); retry++ {
+			for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.Attempt(retry, err, start); retry++ {
 				initTime = time.Now().UTC().Format(time.RFC850)
-				_, err = collectionObject.Collection.Insert(docId, doc, &gocb.InsertOptions{
+				var result *gocb.MutationResult
+				result, err = collectionObject.Collection.Insert(docId, doc, &gocb.InsertOptions{
 					DurabilityLevel: cb_sdk.GetDurability(task.InsertOptions.Durability),
 					PersistTo:       task.InsertOptions.PersistTo,
 					ReplicateTo:     task.InsertOptions.ReplicateTo,
@@ -224,8 +224,15 @@ func insertDocuments(task *InsertTask, collectionObject *cb_sdk.CollectionObject
 					Expiry:          time.Duration(task.InsertOptions.Expiry) * time.Second,
 				})
 				if err == nil {
+					collectionObject.RecordMutation(result)
 					break
 				}
+				if cb_sdk.IsAmbiguousError(err) {
+					if exists, _ := documentExists(collectionObject.Collection, docId); exists {
+						err = nil
+						break
+					}
+				}
 			}
 
 			if err != nil {
@@ -241,6 +248,7 @@ func insertDocuments(task *InsertTask, collectionObject *cb_sdk.CollectionObject
 				}
 			}
 
+			task.Result.RecordSuccess(docId)
 			task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
 			<-routineLimiter
 			return nil
@@ -309,7 +317,7 @@ func (task *InsertTask) PostTaskExceptionHandling(collectionObject *cb_sdk.Colle
 
 					initTime := time.Now().UTC().Format(time.RFC850)
 
-					for retry = 0; retry <= task.OperationConfig.Exceptions.RetryAttempts; retry++ {
+					for start := time.Now(); task.OperationConfig.Exceptions.RetryAttempt(retry, err, start); retry++ {
 						result, err = collectionObject.Collection.Insert(docId, doc, &gocb.InsertOptions{
 							DurabilityLevel: cb_sdk.GetDurability(task.InsertOptions.Durability),
 							PersistTo:       task.InsertOptions.PersistTo,
@@ -319,8 +327,15 @@ func (task *InsertTask) PostTaskExceptionHandling(collectionObject *cb_sdk.Colle
 						})
 
 						if err == nil {
+							collectionObject.RecordMutation(result)
 							break
 						}
+						if cb_sdk.IsAmbiguousError(err) {
+							if exists, _ := documentExists(collectionObject.Collection, docId); exists {
+								err = gocb.ErrDocumentExists
+								break
+							}
+						}
 					}
 
 					if err != nil {
@@ -1,6 +1,7 @@
 package bulk_loading_cb
 
 import (
+	"errors"
 	"fmt"
 	"github.com/couchbase/gocb/v2"
 	"github.com/couchbaselabs/sirius/internal/cb_sdk"
@@ -13,7 +14,6 @@ import (
 	"github.com/couchbaselabs/sirius/internal/template"
 	"golang.org/x/sync/errgroup"
 	"log"
-	"math"
 	"strings"
 	"sync"
 	"time"
@@ -25,6 +25,7 @@ type ReadTask struct {
 	Bucket          string                        `json:"bucket" doc:"true"`
 	Scope           string                        `json:"scope,omitempty" doc:"true"`
 	Collection      string                        `json:"collection,omitempty" doc:"true"`
+	ReadOptions     *cb_sdk.ReadOptions           `json:"readOptions,omitempty" doc:"true"`
 	OperationConfig *OperationConfig              `json:"operationConfig,omitempty" doc:"true"`
 	Operation       string                        `json:"operation" doc:"false"`
 	ResultSeed      int64                         `json:"resultSeed" doc:"false"`
@@ -39,7 +40,11 @@ type ReadTask struct {
 }
 
 func (task *ReadTask) Describe() string {
-	return "Read BulkTask get documents from bucket and validate them with the expected ones"
+	return `Read BulkTask get documents from bucket and validate them with the expected ones.
+The read mode is selected by readOptions.readMode as get (default, supports projections), getAndTouch
+(updates expiry), exists, getAnyReplica or getAllReplicas. Source of the document is recorded in result.
+Documents which do not exist are failures in every read mode. The expiry set by getAndTouch is not tracked, so
+validating the documents afterwards does not account for their expiry.`
 }
 
 func (task *ReadTask) CollectionIdentifier() string {
@@ -95,6 +100,14 @@ func (task *ReadTask) Config(req *tasks.Request, reRun bool) (int64, error) {
 			task.Collection = cb_sdk.DefaultCollection
 		}
 
+		if task.ReadOptions == nil {
+			task.ReadOptions = &cb_sdk.ReadOptions{}
+		}
+		if err := cb_sdk.ConfigReadOptions(task.ReadOptions); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
 		if err := ConfigureOperationConfig(task.OperationConfig); err != nil {
 			task.TaskPending = false
 			return 0, fmt.Errorf(err.Error())
@@ -110,6 +123,12 @@ func (task *ReadTask) Config(req *tasks.Request, reRun bool) (int64, error) {
 			return task.ResultSeed, task_errors.ErrTaskStateIsNil
 		}
 
+		// read tasks stored before read modes were introduced don't have ReadOptions.
+		if task.ReadOptions == nil {
+			task.ReadOptions = &cb_sdk.ReadOptions{}
+			_ = cb_sdk.ConfigReadOptions(task.ReadOptions)
+		}
+
 		task.State.SetupStoringKeys()
 		_ = task_result.DeleteResultFile(task.ResultSeed)
 		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
@@ -138,12 +157,26 @@ func (task *ReadTask) Do() error {
 		return task.TearUp()
 	}
 
+	task.Result.SetKeyLocator(collectionObject, task.OperationConfig.NodeSampleRate)
 	getDocuments(task, collectionObject)
 	task.Result.Success = task.OperationConfig.End - task.OperationConfig.Start - task.Result.Failure
 
 	return task.TearUp()
 }
 
+// readDocument reads a document using the configured read mode and records the source it was read from.
+func (task *ReadTask) readDocument(collectionObject *cb_sdk.CollectionObject, docId string) (uint64, error) {
+	result, err := cb_sdk.ReadDocument(collectionObject.Collection, docId, task.ReadOptions)
+	if err != nil {
+		if result.ReadMode == cb_sdk.ReadModeExists && errors.Is(err, gocb.ErrDocumentNotFound) {
+			task.Result.RecordRead(result)
+		}
+		return 0, err
+	}
+	task.Result.RecordRead(result)
+	return uint64(result.Cas), nil
+}
+
 // getDocuments reads the documents in the bucket
 func getDocuments(task *ReadTask, collectionObject *cb_sdk.CollectionObject) {
 
@@ -183,10 +216,9 @@ func getDocuments(task *ReadTask, collectionObject *cb_sdk.CollectionObject) {
 
 			initTime := time.Now().UTC().Format(time.RFC850)
 			var err error
-			for retry := 0; retry < int(math.Max(float64(1), float64(task.OperationConfig.Exceptions.
-				RetryAttempts))); retry++ {
+			for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.Attempt(retry, err, start); retry++ {
 				initTime = time.Now().UTC().Format(time.RFC850)
-				_, err = collectionObject.Collection.Get(docId, nil)
+				_, err = task.readDocument(collectionObject, docId)
 				if err == nil {
 					break
 				}
@@ -199,6 +231,7 @@ func getDocuments(task *ReadTask, collectionObject *cb_sdk.CollectionObject) {
 				return err
 			}
 
+			task.Result.RecordSuccess(docId)
 			task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
 			<-routineLimiter
 			return nil
@@ -257,11 +290,11 @@ func (task *ReadTask) PostTaskExceptionHandling(collectionObject *cb_sdk.Collect
 					docId := task.gen.BuildKey(key)
 
 					retry := 0
-					result := &gocb.GetResult{}
+					var cas uint64
 					var err error
 					initTime := time.Now().UTC().Format(time.RFC850)
-					for retry = 0; retry <= task.OperationConfig.Exceptions.RetryAttempts; retry++ {
-						result, err = collectionObject.Collection.Get(docId, nil)
+					for start := time.Now(); task.OperationConfig.Exceptions.RetryAttempt(retry, err, start); retry++ {
+						cas, err = task.readDocument(collectionObject, docId)
 
 						if err == nil {
 							break
@@ -271,7 +304,7 @@ func (task *ReadTask) PostTaskExceptionHandling(collectionObject *cb_sdk.Collect
 					if err == nil {
 						m[offset] = RetriedResult{
 							Status:   true,
-							CAS:      uint64(result.Cas()),
+							CAS:      cas,
 							InitTime: initTime,
 							AckTime:  time.Now().UTC().Format(time.RFC850),
 						}
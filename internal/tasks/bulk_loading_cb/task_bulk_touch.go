@@ -13,7 +13,6 @@ import (
 	"github.com/couchbaselabs/sirius/internal/template"
 	"golang.org/x/sync/errgroup"
 	"log"
-	"math"
 	"strings"
 	"sync"
 	"time"
@@ -152,6 +151,7 @@ func (task *TouchTask) Do() error {
 		return task.TearUp()
 	}
 
+	task.Result.SetKeyLocator(collectionObject, task.OperationConfig.NodeSampleRate)
 	touchDocuments(task, collectionObject)
 	task.Result.Success = task.OperationConfig.End - task.OperationConfig.Start - task.Result.Failure
 
@@ -198,15 +198,16 @@ func touchDocuments(task *TouchTask, collectionObject *cb_sdk.CollectionObject)
 
 			initTime := time.Now().UTC().Format(time.RFC850)
 
-			for retry := 0; retry < int(math.Max(float64(1), float64(task.OperationConfig.Exceptions.
-				RetryAttempts))); retry++ {
+			for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.Attempt(retry, err, start); retry++ {
 				initTime = time.Now().UTC().Format(time.RFC850)
-				_, err = collectionObject.Collection.Touch(docId, time.Duration(task.Expiry)*time.Second,
+				var result *gocb.MutationResult
+				result, err = collectionObject.Collection.Touch(docId, time.Duration(task.Expiry)*time.Second,
 					&gocb.TouchOptions{
 						Timeout: time.Duration(task.TouchOptions.Timeout) * time.Second,
 					})
 
 				if err == nil {
+					collectionObject.RecordMutation(result)
 					break
 				}
 			}
@@ -218,6 +219,7 @@ func touchDocuments(task *TouchTask, collectionObject *cb_sdk.CollectionObject)
 				return err
 			}
 
+			task.Result.RecordSuccess(docId)
 			task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
 
 			<-routineLimiter
@@ -279,13 +281,14 @@ func (task *TouchTask) PostTaskExceptionHandling(collectionObject *cb_sdk.Collec
 					result := &gocb.MutationResult{}
 					var err error
 					initTime := time.Now().UTC().Format(time.RFC850)
-					for retry := 0; retry <= task.OperationConfig.Exceptions.RetryAttempts; retry++ {
-						_, err := collectionObject.Collection.Touch(docId, time.Duration(task.Expiry)*time.Second,
+					for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.RetryAttempt(retry, err, start); retry++ {
+						result, err = collectionObject.Collection.Touch(docId, time.Duration(task.Expiry)*time.Second,
 							&gocb.TouchOptions{
 								Timeout: time.Duration(task.TouchOptions.Timeout) * time.Second,
 							})
 
 						if err == nil {
+							collectionObject.RecordMutation(result)
 							break
 						}
 					}
@@ -1,6 +1,7 @@
 package bulk_loading_cb
 
 import (
+	"errors"
 	"fmt"
 	"github.com/couchbase/gocb/v2"
 	"github.com/couchbaselabs/sirius/internal/cb_sdk"
@@ -14,7 +15,6 @@ import (
 	"github.com/jaswdr/faker"
 	"golang.org/x/sync/errgroup"
 	"log"
-	"math"
 	"math/rand"
 	"strings"
 	"sync"
@@ -28,6 +28,7 @@ type UpsertTask struct {
 	Scope           string                        `json:"scope,omitempty" doc:"true"`
 	Collection      string                        `json:"collection,omitempty" doc:"true"`
 	InsertOptions   *cb_sdk.InsertOptions         `json:"insertOptions,omitempty" doc:"true"`
+	CasOptions      *cb_sdk.CasOptions            `json:"casOptions,omitempty" doc:"true"`
 	OperationConfig *OperationConfig              `json:"operationConfig,omitempty" doc:"true"`
 	Operation       string                        `json:"operation" doc:"false"`
 	ResultSeed      int64                         `json:"resultSeed" doc:"false"`
@@ -50,7 +51,11 @@ func (task *UpsertTask) CollectionIdentifier() string {
 func (task *UpsertTask) Describe() string {
 	return `Upsert task mutates documents in bulk into a bucket.
 The task will update the fields in a documents ranging from [start,end] inclusive.
-We need to share the fields we want to update in a json document using SQL++ syntax.`
+We need to share the fields we want to update in a json document using SQL++ syntax.
+If casOptions are shared, every document is replaced only if it still has the CAS read before updating it, and
+casOptions.conflictRate of the documents are mutated concurrently to inject CAS conflicts. CAS mismatches are
+reported in the cas section of the result. Documents which do not exist have no CAS to guard with and are inserted,
+which is reported in the cas section as well.`
 }
 
 func (task *UpsertTask) CheckIfPending() bool {
@@ -94,6 +99,13 @@ func (task *UpsertTask) Config(req *tasks.Request, reRun bool) (int64, error) {
 			return 0, err
 		}
 
+		if task.CasOptions != nil {
+			if err := cb_sdk.ConfigCasOptions(task.CasOptions); err != nil {
+				task.TaskPending = false
+				return 0, err
+			}
+		}
+
 		if err := ConfigureOperationConfig(task.OperationConfig); err != nil {
 			task.TaskPending = false
 			return 0, err
@@ -152,6 +164,7 @@ func (task *UpsertTask) Do() error {
 		return task.TearUp()
 	}
 
+	task.Result.SetKeyLocator(collectionObject, task.OperationConfig.NodeSampleRate)
 	upsertDocuments(task, collectionObject)
 	task.Result.Success = task.OperationConfig.End - task.OperationConfig.Start - task.Result.Failure
 
@@ -213,18 +226,13 @@ func upsertDocuments(task *UpsertTask, collectionObject *cb_sdk.CollectionObject
 			docUpdated, err := task.gen.Template.UpdateDocument(task.OperationConfig.FieldsToChange, originalDoc,
 				task.OperationConfig.DocSize, &fake)
 
-			for retry := 0; retry < int(math.Max(float64(1), float64(task.OperationConfig.Exceptions.
-				RetryAttempts))); retry++ {
+			for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.Attempt(retry, err, start); retry++ {
 				initTime = time.Now().UTC().Format(time.RFC850)
-				_, err = collectionObject.Collection.Upsert(docId, docUpdated, &gocb.UpsertOptions{
-					DurabilityLevel: cb_sdk.GetDurability(task.InsertOptions.Durability),
-					PersistTo:       task.InsertOptions.PersistTo,
-					ReplicateTo:     task.InsertOptions.ReplicateTo,
-					Timeout:         time.Duration(task.InsertOptions.Timeout) * time.Second,
-					Expiry:          time.Duration(task.InsertOptions.Expiry) * time.Second,
-				})
+				var result *gocb.MutationResult
+				result, err = task.upsertDocument(collectionObject, docId, docUpdated, retry == 0)
 
 				if err == nil {
+					collectionObject.RecordMutation(result)
 					break
 				}
 			}
@@ -236,6 +244,7 @@ func upsertDocuments(task *UpsertTask, collectionObject *cb_sdk.CollectionObject
 				return err
 			}
 
+			task.Result.RecordSuccess(docId)
 			task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
 
 			<-routineLimiter
@@ -249,6 +258,46 @@ func upsertDocuments(task *UpsertTask, collectionObject *cb_sdk.CollectionObject
 	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
 }
 
+// upsertDocument upserts a document or, if casOptions are shared, replaces it if it still has the CAS read before
+// replacing it. A CAS conflict is injected on the first attempt for casOptions.conflictRate of the documents. A
+// document which does not exist has no CAS to guard with and is inserted instead.
+func (task *UpsertTask) upsertDocument(collectionObject *cb_sdk.CollectionObject, docId string, doc interface{},
+	firstAttempt bool) (*gocb.MutationResult, error) {
+	if task.CasOptions == nil {
+		return collectionObject.Collection.Upsert(docId, doc, &gocb.UpsertOptions{
+			DurabilityLevel: cb_sdk.GetDurability(task.InsertOptions.Durability),
+			PersistTo:       task.InsertOptions.PersistTo,
+			ReplicateTo:     task.InsertOptions.ReplicateTo,
+			Timeout:         time.Duration(task.InsertOptions.Timeout) * time.Second,
+			Expiry:          time.Duration(task.InsertOptions.Expiry) * time.Second,
+			PreserveExpiry:  task.InsertOptions.PreserveExpiry,
+		})
+	}
+
+	injectConflict := firstAttempt && rand.Float64() < task.CasOptions.ConflictRate
+	result, err := cb_sdk.ReplaceWithCas(collectionObject.Collection, docId, doc, gocb.ReplaceOptions{
+		DurabilityLevel: cb_sdk.GetDurability(task.InsertOptions.Durability),
+		PersistTo:       task.InsertOptions.PersistTo,
+		ReplicateTo:     task.InsertOptions.ReplicateTo,
+		Timeout:         time.Duration(task.InsertOptions.Timeout) * time.Second,
+		Expiry:          time.Duration(task.InsertOptions.Expiry) * time.Second,
+		PreserveExpiry:  task.InsertOptions.PreserveExpiry,
+	}, injectConflict)
+	if errors.Is(err, gocb.ErrDocumentNotFound) {
+		result, err = collectionObject.Collection.Insert(docId, doc, &gocb.InsertOptions{
+			DurabilityLevel: cb_sdk.GetDurability(task.InsertOptions.Durability),
+			PersistTo:       task.InsertOptions.PersistTo,
+			ReplicateTo:     task.InsertOptions.ReplicateTo,
+			Timeout:         time.Duration(task.InsertOptions.Timeout) * time.Second,
+			Expiry:          time.Duration(task.InsertOptions.Expiry) * time.Second,
+		})
+		task.Result.RecordCasInsert(err)
+		return result, err
+	}
+	task.Result.RecordCasReplace(injectConflict, err)
+	return result, err
+}
+
 func (task *UpsertTask) PostTaskExceptionHandling(collectionObject *cb_sdk.CollectionObject) {
 	task.Result.StopStoringResult()
 	task.State.StopStoringState()
@@ -318,16 +367,11 @@ func (task *UpsertTask) PostTaskExceptionHandling(collectionObject *cb_sdk.Colle
 					result := &gocb.MutationResult{}
 
 					initTime := time.Now().UTC().Format(time.RFC850)
-					for retry = 0; retry <= task.OperationConfig.Exceptions.RetryAttempts; retry++ {
-						result, err = collectionObject.Collection.Upsert(docId, docUpdated, &gocb.UpsertOptions{
-							DurabilityLevel: cb_sdk.GetDurability(task.InsertOptions.Durability),
-							PersistTo:       task.InsertOptions.PersistTo,
-							ReplicateTo:     task.InsertOptions.ReplicateTo,
-							Timeout:         time.Duration(task.InsertOptions.Timeout) * time.Second,
-							Expiry:          time.Duration(task.InsertOptions.Expiry) * time.Second,
-						})
+					for start := time.Now(); task.OperationConfig.Exceptions.RetryAttempt(retry, err, start); retry++ {
+						result, err = task.upsertDocument(collectionObject, docId, docUpdated, false)
 
 						if err == nil {
+							collectionObject.RecordMutation(result)
 							break
 						}
 					}
@@ -0,0 +1,384 @@
+package bulk_loading_cb
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"golang.org/x/sync/errgroup"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultFanOutConcurrency = 8
+	AllNames                 = "*"
+)
+
+// fanOutTasks returns a new task for the bulk operations which can be fanned out across collections.
+var fanOutTasks = map[string]func() BulkTask{
+	tasks.InsertOperation:           func() BulkTask { return &InsertTask{} },
+	tasks.UpsertOperation:           func() BulkTask { return &UpsertTask{} },
+	tasks.DeleteOperation:           func() BulkTask { return &DeleteTask{} },
+	tasks.ReadOperation:             func() BulkTask { return &ReadTask{} },
+	tasks.TouchOperation:            func() BulkTask { return &TouchTask{} },
+	tasks.ValidateOperation:         func() BulkTask { return &ValidateTask{} },
+	tasks.SubDocInsertOperation:     func() BulkTask { return &SubDocInsert{} },
+	tasks.SubDocUpsertOperation:     func() BulkTask { return &SubDocUpsert{} },
+	tasks.SubDocDeleteOperation:     func() BulkTask { return &SubDocDelete{} },
+	tasks.SubDocReadOperation:       func() BulkTask { return &SubDocRead{} },
+	tasks.SubDocReplaceOperation:    func() BulkTask { return &SubDocReplace{} },
+	tasks.IncrementOperation:        func() BulkTask { return &IncrementTask{} },
+	tasks.DecrementOperation:        func() BulkTask { return &DecrementTask{} },
+	tasks.AppendOperation:           func() BulkTask { return &AppendTask{} },
+	tasks.PrependOperation:          func() BulkTask { return &PrependTask{} },
+	tasks.LockOperation:             func() BulkTask { return &LockTask{} },
+	tasks.ScanOperation:             func() BulkTask { return &ScanTask{} },
+	tasks.TransactionOperation:      func() BulkTask { return &TransactionTask{} },
+	tasks.VerifyDurabilityOperation: func() BulkTask { return &VerifyDurabilityTask{} },
+}
+
+// NewBulkTask returns a new task for a bulk operation which can be fanned out across collections.
+func NewBulkTask(operation string) (BulkTask, error) {
+	newTask, ok := fanOutTasks[operation]
+	if !ok {
+		return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidFanOutOperation, operation)
+	}
+	return newTask(), nil
+}
+
+// FanOutSubTask is the bulk task run on a collection as a part of FanOutTask.
+type FanOutSubTask struct {
+	Collection string   `json:"collection"`
+	ResultSeed int64    `json:"resultSeed"`
+	Task       BulkTask `json:"-"`
+}
+
+type FanOutTask struct {
+	IdentifierToken string                  `json:"identifierToken" doc:"true"`
+	ClusterConfig   *cb_sdk.ClusterConfig   `json:"clusterConfig" doc:"true"`
+	Bucket          string                  `json:"bucket" doc:"true"`
+	Collections     string                  `json:"collections" doc:"true"`
+	BulkOperation   string                  `json:"bulkOperation" doc:"true"`
+	Distribute      bool                    `json:"distribute,omitempty" doc:"true"`
+	Concurrency     int                     `json:"concurrency,omitempty" doc:"true"`
+	Task            json.RawMessage         `json:"task" doc:"true"`
+	SubTasks        []FanOutSubTask         `json:"subTasks" doc:"false"`
+	Operation       string                  `json:"operation" doc:"false"`
+	ResultSeed      int64                   `json:"resultSeed" doc:"false"`
+	TaskPending     bool                    `json:"taskPending" doc:"false"`
+	Result          *task_result.TaskResult `json:"-" doc:"false"`
+	req             *tasks.Request          `json:"-" doc:"false"`
+}
+
+func (task *FanOutTask) Describe() string {
+	return `Fan out task runs a bulk operation on many collections of a bucket in a single request. collections is a
+comma separated list of scope.collection patterns where every [start-end] is expanded to the numbers in the range
+and * matches every scope or collection, e.g. scope_[0-99].coll_[0-9] or scope_1.*. bulkOperation is the operation,
+e.g. insert or upsert, and task holds its options, i.e. the body of its bulk endpoint without the cluster, bucket,
+scope and collection. Every collection is loaded with the key range of operationConfig, or a share of it if
+distribute is set, by a bulk task of its own, keeping the meta data of every collection separate. The result has
+the success, failure and result seed of the bulk task of every collection.`
+}
+
+func (task *FanOutTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+// Subtasks returns the bulk tasks run on every collection.
+func (task *FanOutTask) Subtasks() []tasks.TaskWithIdentifier {
+	var subtasks []tasks.TaskWithIdentifier
+	for _, s := range task.SubTasks {
+		subtasks = append(subtasks, tasks.TaskWithIdentifier{Operation: task.BulkOperation, Task: s.Task})
+	}
+	return subtasks
+}
+
+func (task *FanOutTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+		task.Operation = tasks.FanOutOperation
+
+		if task.Bucket == "" {
+			task.Bucket = cb_sdk.DefaultBucket
+		}
+		if task.Concurrency <= 0 {
+			task.Concurrency = DefaultFanOutConcurrency
+		}
+		if _, err := NewBulkTask(task.BulkOperation); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+		collections, err := task.resolveCollections()
+		if err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+		subTasks := make([]FanOutSubTask, 0, len(collections))
+		for i, collection := range collections {
+			subTask, err := task.buildSubTask(collection, i, len(collections))
+			if err != nil {
+				task.TaskPending = false
+				return 0, fmt.Errorf("%s : %w", collection, err)
+			}
+			subTasks = append(subTasks, subTask)
+		}
+		// the subtasks are discarded if one of them fails to configure, so the key ranges reserved by the ones
+		// configured before it are released.
+		reserved := make([]int64, len(subTasks))
+		for i := range subTasks {
+			metaData := req.MetaData.GetCollectionMetadata(subTasks[i].Task.CollectionIdentifier())
+			req.Lock()
+			seedEnd := metaData.SeedEnd
+			req.Unlock()
+			resultSeed, err := subTasks[i].Task.Config(req, false)
+			req.Lock()
+			reserved[i] = metaData.SeedEnd - seedEnd
+			req.Unlock()
+			if err != nil {
+				task.releaseSeedEnds(subTasks[:i+1], reserved)
+				task.TaskPending = false
+				return 0, fmt.Errorf("%s : %w", subTasks[i].Collection, err)
+			}
+			subTasks[i].ResultSeed = resultSeed
+		}
+		task.SubTasks = subTasks
+
+	} else {
+		for _, s := range task.SubTasks {
+			if s.Task != nil && s.Task.CheckIfPending() {
+				if _, err := s.Task.Config(req, true); err != nil {
+					log.Println(s.Collection, err.Error())
+				}
+			}
+		}
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+	return task.ResultSeed, nil
+}
+
+// releaseSeedEnds takes back the key range reserved in the meta data of its collection by every subtask.
+func (task *FanOutTask) releaseSeedEnds(subTasks []FanOutSubTask, reserved []int64) {
+	task.req.Lock()
+	defer task.req.Unlock()
+	for i := range subTasks {
+		if reserved[i] > 0 {
+			metaData := task.req.MetaData.GetCollectionMetadata(subTasks[i].Task.CollectionIdentifier())
+			task.req.AddToSeedEnd(metaData, -reserved[i])
+		}
+	}
+}
+
+func (task *FanOutTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed, task.Operation)
+	}
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *FanOutTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	routineLimiter := make(chan struct{}, task.Concurrency)
+	group := errgroup.Group{}
+	for i := range task.SubTasks {
+		subTask := task.SubTasks[i]
+		if task.req.ContextClosed() {
+			break
+		}
+		if subTask.Task == nil || !subTask.Task.CheckIfPending() {
+			continue
+		}
+		routineLimiter <- struct{}{}
+		group.Go(func() error {
+			err := subTask.Task.Do()
+			<-routineLimiter
+			return err
+		})
+	}
+	_ = group.Wait()
+	close(routineLimiter)
+
+	for _, subTask := range task.SubTasks {
+		collectionResult := task_result.CollectionResult{ResultSeed: subTask.ResultSeed}
+		if result, err := task_result.ReadResultFromFile(fmt.Sprintf("%d", subTask.ResultSeed), false); err == nil {
+			collectionResult.Success = result.Success
+			collectionResult.Failure = result.Failure
+			collectionResult.ErrorOther = result.ErrorOther
+		} else {
+			collectionResult.ErrorOther = err.Error()
+		}
+		task.Result.RecordCollectionResult(subTask.Collection, collectionResult)
+	}
+
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	return task.TearUp()
+}
+
+// resolveCollections returns the scope.collection names matching the collections patterns.
+func (task *FanOutTask) resolveCollections() ([]string, error) {
+	var existing []gocb.ScopeSpec
+	var collections []string
+	seen := make(map[string]struct{})
+
+	for _, pattern := range strings.Split(task.Collections, ",") {
+		scopePattern, collectionPattern, found := strings.Cut(strings.TrimSpace(pattern), ".")
+		if !found || scopePattern == "" || collectionPattern == "" {
+			return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidNamePattern, pattern)
+		}
+
+		var names []string
+		if scopePattern == AllNames || collectionPattern == AllNames {
+			if existing == nil {
+				bucket, err := task.req.GetBucket(task.ClusterConfig, task.Bucket)
+				if err != nil {
+					return nil, err
+				}
+				if existing, err = bucket.Collections().GetAllScopes(nil); err != nil {
+					return nil, err
+				}
+			}
+			var err error
+			if names, err = matchExistingCollections(existing, scopePattern, collectionPattern); err != nil {
+				return nil, err
+			}
+		} else {
+			scopes, err := cb_sdk.ExpandNamePattern(scopePattern)
+			if err != nil {
+				return nil, err
+			}
+			collectionNames, err := cb_sdk.ExpandNamePattern(collectionPattern)
+			if err != nil {
+				return nil, err
+			}
+			for _, scope := range scopes {
+				for _, collection := range collectionNames {
+					names = append(names, scope+"."+collection)
+				}
+			}
+		}
+
+		for _, name := range names {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				collections = append(collections, name)
+			}
+		}
+	}
+
+	if len(collections) == 0 {
+		return nil, fmt.Errorf("%w : %s", task_errors.ErrNoCollectionsToFanOut, task.Collections)
+	}
+	return collections, nil
+}
+
+// matchExistingCollections returns the existing scope.collection names matching a scope and a collection pattern
+// where * matches every name.
+func matchExistingCollections(scopes []gocb.ScopeSpec, scopePattern, collectionPattern string) ([]string, error) {
+	matches := func(pattern string) (func(string) bool, error) {
+		if pattern == AllNames {
+			return func(string) bool { return true }, nil
+		}
+		names, err := cb_sdk.ExpandNamePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		set := make(map[string]struct{})
+		for _, name := range names {
+			set[name] = struct{}{}
+		}
+		return func(name string) bool {
+			_, ok := set[name]
+			return ok
+		}, nil
+	}
+
+	scopeMatches, err := matches(scopePattern)
+	if err != nil {
+		return nil, err
+	}
+	collectionMatches, err := matches(collectionPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, scope := range scopes {
+		if !scopeMatches(scope.Name) {
+			continue
+		}
+		for _, collection := range scope.Collections {
+			if collectionMatches(collection.Name) {
+				names = append(names, scope.Name+"."+collection.Name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// buildSubTask returns the bulk task for the index-th of total collections, built from the body of the task with
+// the cluster, bucket, scope and collection of the fan out task. If Distribute is set, the key range is split
+// between the collections.
+func (task *FanOutTask) buildSubTask(collection string, index int, total int) (FanOutSubTask, error) {
+	body := make(map[string]interface{})
+	if len(task.Task) > 0 {
+		if err := json.Unmarshal(task.Task, &body); err != nil {
+			return FanOutSubTask{}, err
+		}
+	}
+	scope, collectionName, _ := strings.Cut(collection, ".")
+	body["identifierToken"] = task.IdentifierToken
+	body["clusterConfig"] = task.ClusterConfig
+	body["bucket"] = task.Bucket
+	body["scope"] = scope
+	body["collection"] = collectionName
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return FanOutSubTask{}, err
+	}
+	bulkTask, err := NewBulkTask(task.BulkOperation)
+	if err != nil {
+		return FanOutSubTask{}, err
+	}
+	if err := json.Unmarshal(b, bulkTask); err != nil {
+		return FanOutSubTask{}, err
+	}
+
+	if task.Distribute {
+		if operationConfig, _ := bulkTask.GetOperationConfig(); operationConfig != nil {
+			operationConfig.Start, operationConfig.End = distributeRange(operationConfig.Start, operationConfig.End,
+				index, total)
+		}
+	}
+	return FanOutSubTask{Collection: collection, Task: bulkTask}, nil
+}
+
+// distributeRange returns the share of the index-th of total collections from the key range [start, end).
+func distributeRange(start, end int64, index, total int) (int64, int64) {
+	size := end - start
+	return start + size*int64(index)/int64(total), start + size*int64(index+1)/int64(total)
+}
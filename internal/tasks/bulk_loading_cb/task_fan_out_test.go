@@ -0,0 +1,68 @@
+package bulk_loading_cb
+
+import (
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"reflect"
+	"testing"
+)
+
+func TestFanOutSubTasks(t *testing.T) {
+	task := &FanOutTask{
+		IdentifierToken: "test",
+		ClusterConfig:   &cb_sdk.ClusterConfig{ConnectionString: "couchbase://127.0.0.1"},
+		Bucket:          "b",
+		BulkOperation:   tasks.InsertOperation,
+		Distribute:      true,
+		Task:            []byte(`{"scope":"ignored","operationConfig":{"start":0,"end":10,"template":"person"}}`),
+	}
+
+	var ranges [][2]int64
+	for i, collection := range []string{"s0.c0", "s0.c1", "s1.c0"} {
+		subTask, err := task.buildSubTask(collection, i, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		insertTask := subTask.Task.(*InsertTask)
+		if insertTask.Bucket != "b" || insertTask.Scope+"."+insertTask.Collection != collection {
+			t.Fatalf("expected %s, got %s.%s", collection, insertTask.Scope, insertTask.Collection)
+		}
+		ranges = append(ranges, [2]int64{insertTask.OperationConfig.Start, insertTask.OperationConfig.End})
+		task.SubTasks = append(task.SubTasks, subTask)
+	}
+	if !reflect.DeepEqual(ranges, [][2]int64{{0, 3}, {3, 6}, {6, 10}}) {
+		t.Fatalf("key range not distributed : %v", ranges)
+	}
+
+	r := tasks.NewRequest("test")
+	r.Tasks = []tasks.TaskWithIdentifier{{Operation: tasks.FanOutOperation, Task: task}}
+	if allTasks := r.AllTasks(); len(allTasks) != 4 || allTasks[3].Operation != tasks.InsertOperation {
+		t.Fatalf("sub tasks not visible in request : %v", allTasks)
+	}
+
+	// the list of tasks is cached until the sub tasks change.
+	task.SubTasks = task.SubTasks[:1]
+	if allTasks := r.AllTasks(); len(allTasks) != 4 {
+		t.Fatalf("expected the cached tasks, got %v", allTasks)
+	}
+	r.InvalidateTasks()
+	if allTasks := r.AllTasks(); len(allTasks) != 2 {
+		t.Fatalf("expected the tasks to be built again, got %v", allTasks)
+	}
+}
+
+func TestMatchExistingCollections(t *testing.T) {
+	scopes := []gocb.ScopeSpec{
+		{Name: "s0", Collections: []gocb.CollectionSpec{{Name: "c0"}, {Name: "c1"}}},
+		{Name: "s1", Collections: []gocb.CollectionSpec{{Name: "c0"}}},
+	}
+	names, err := matchExistingCollections(scopes, "s0", AllNames)
+	if err != nil || !reflect.DeepEqual(names, []string{"s0.c0", "s0.c1"}) {
+		t.Fatalf("unexpected collections %v %v", names, err)
+	}
+	names, err = matchExistingCollections(scopes, AllNames, "c[0-0]")
+	if err != nil || !reflect.DeepEqual(names, []string{"s0.c0", "s1.c0"}) {
+		t.Fatalf("unexpected collections %v %v", names, err)
+	}
+}
@@ -21,7 +21,8 @@ func (r *RetryExceptions) Describe() string {
 	return "Retry Exception reties failed operations.\n" +
 		"IgnoreExceptions will ignore failed operation occurred in this category. \n" +
 		"RetryExceptions will retry failed operation occurred in this category. \n" +
-		"RetryAttempts is the number of retry attempts.\n"
+		"RetryAttempts is the number of retry attempts.\n" +
+		"RetryPolicy configures the backoff between retries and ExceptionRetries overrides it for specific exceptions.\n"
 }
 
 func (r *RetryExceptions) Do() error {
@@ -49,8 +50,12 @@ func (r *RetryExceptions) Config(req *tasks.Request, reRun bool) (int64, error)
 	if r.req.Tasks == nil {
 		return 0, fmt.Errorf("request.Task struct is nil")
 	}
-	for i := range r.req.Tasks {
-		if bulkTask, ok := r.req.Tasks[i].Task.(BulkTask); ok {
+	if err := r.Exceptions.validate(); err != nil {
+		return 0, err
+	}
+	allTasks := r.req.AllTasks()
+	for i := range allTasks {
+		if bulkTask, ok := allTasks[i].Task.(BulkTask); ok {
 			if ok, err := bulkTask.MatchResultSeed(r.ResultSeed); ok {
 				if err != nil {
 					return 0, err
@@ -0,0 +1,306 @@
+package bulk_loading_cb
+
+import (
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/docgenerator"
+	"github.com/couchbaselabs/sirius/internal/meta_data"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/task_state"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/couchbaselabs/sirius/internal/template"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ScanTask struct {
+	IdentifierToken string                        `json:"identifierToken" doc:"true"`
+	ClusterConfig   *cb_sdk.ClusterConfig         `json:"clusterConfig" doc:"true"`
+	Bucket          string                        `json:"bucket" doc:"true"`
+	Scope           string                        `json:"scope,omitempty" doc:"true"`
+	Collection      string                        `json:"collection,omitempty" doc:"true"`
+	ScanOptions     *cb_sdk.ScanOptions           `json:"scanOptions,omitempty" doc:"true"`
+	OperationConfig *OperationConfig              `json:"operationConfig,omitempty" doc:"true"`
+	Operation       string                        `json:"operation" doc:"false"`
+	ResultSeed      int64                         `json:"resultSeed" doc:"false"`
+	TaskPending     bool                          `json:"taskPending" doc:"false"`
+	State           *task_state.TaskState         `json:"State" doc:"false"`
+	MetaData        *meta_data.CollectionMetaData `json:"metaData" doc:"false"`
+	Result          *task_result.TaskResult       `json:"-" doc:"false"`
+	gen             *docgenerator.Generator       `json:"-" doc:"false"`
+	req             *tasks.Request                `json:"-" doc:"false"`
+	rerun           bool                          `json:"-" doc:"false"`
+	lock            sync.Mutex                    `json:"-" doc:"false"`
+}
+
+func (task *ScanTask) Describe() string {
+	return `Scan task performs a KV range scan or sampling scan on a collection (Couchbase Server 7.6+).
+Range scan covers every key starting with keyPrefix and keeps the ones built from keyPrefix, keySuffix and
+keySize lying in [start,end). If both start and end are 0 the whole key space loaded by Sirius is used.
+Items in the range are checked against the keys Sirius expects to exist as per the previous operations,
+missing and unexpected keys are reported as failures. Sampling scan returns limit random items, which are
+only checked to be expected. Items returned and throughput are reported in the task result.`
+}
+
+func (task *ScanTask) CollectionIdentifier() string {
+	clusterIdentifier, _ := cb_sdk.GetClusterIdentifier(task.ClusterConfig.ConnectionString)
+	return strings.Join([]string{task.IdentifierToken, clusterIdentifier, task.Bucket, task.Scope,
+		task.Collection}, ":")
+}
+
+func (task *ScanTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+func (task *ScanTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	task.lock = sync.Mutex{}
+	task.rerun = reRun
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+		task.Operation = tasks.ScanOperation
+
+		if task.Bucket == "" {
+			task.Bucket = cb_sdk.DefaultBucket
+		}
+		if task.Scope == "" {
+			task.Scope = cb_sdk.DefaultScope
+		}
+		if task.Collection == "" {
+			task.Collection = cb_sdk.DefaultCollection
+		}
+
+		if err := cb_sdk.ConfigScanOptions(task.ScanOptions); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+		if err := ConfigureOperationConfig(task.OperationConfig); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+		task.MetaData = task.req.MetaData.GetCollectionMetadata(task.CollectionIdentifier())
+		task.req.Lock()
+		task.State = task_state.ConfigTaskState(task.MetaData.Seed, task.MetaData.SeedEnd, task.ResultSeed)
+		task.req.Unlock()
+
+	} else {
+		if task.State == nil {
+			return task.ResultSeed, task_errors.ErrTaskStateIsNil
+		}
+
+		task.State.SetupStoringKeys()
+		_ = task_result.DeleteResultFile(task.ResultSeed)
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+	return task.ResultSeed, nil
+}
+
+func (task *ScanTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed, task.Operation)
+	}
+	task.Result = nil
+	task.State.StopStoringState()
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *ScanTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	collectionObject, err1 := task.GetCollectionObject()
+
+	task.gen = docgenerator.ConfigGenerator(
+		task.OperationConfig.KeySize,
+		task.OperationConfig.DocSize,
+		task.OperationConfig.DocType,
+		task.OperationConfig.KeyPrefix,
+		task.OperationConfig.KeySuffix,
+		template.InitialiseTemplate(task.OperationConfig.TemplateName))
+
+	if err1 != nil {
+		task.Result.ErrorOther = err1.Error()
+		task.Result.Failure++
+		return task.TearUp()
+	}
+
+	scanDocuments(task, collectionObject)
+
+	return task.TearUp()
+}
+
+// scanRange returns the range of offsets covered by the scan.
+func (task *ScanTask) scanRange() (int64, int64) {
+	if task.OperationConfig.Start == 0 && task.OperationConfig.End == 0 {
+		return 0, task.MetaData.SeedEnd - task.MetaData.Seed
+	}
+	return task.OperationConfig.Start, task.OperationConfig.End
+}
+
+// runScan performs the scan once and returns the IDs of the items returned.
+func (task *ScanTask) runScan(collectionObject *cb_sdk.CollectionObject) ([]string, error) {
+	var scanType gocb.ScanType
+	if task.ScanOptions.ScanType == cb_sdk.ScanTypeSampling {
+		scanType = gocb.SamplingScan{
+			Limit: task.ScanOptions.Limit,
+			Seed:  task.ScanOptions.Seed,
+		}
+	} else {
+		scanType = gocb.NewRangeScanForPrefix(task.OperationConfig.KeyPrefix)
+	}
+
+	scanResult, err := collectionObject.Collection.Scan(scanType, &gocb.ScanOptions{
+		IDsOnly:        task.ScanOptions.IdsOnly,
+		BatchByteLimit: task.ScanOptions.BatchByteLimit,
+		BatchItemLimit: task.ScanOptions.BatchItemLimit,
+		Timeout:        time.Duration(task.ScanOptions.Timeout) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var docIds []string
+	for item := scanResult.Next(); item != nil; item = scanResult.Next() {
+		docIds = append(docIds, item.ID())
+	}
+	if err := scanResult.Close(); err != nil {
+		return nil, err
+	}
+	return docIds, scanResult.Err()
+}
+
+// scanDocuments scans the collection and compares the returned keys with the keys expected to exist, which are the
+// keys of the key space successfully written and not deleted afterwards.
+func scanDocuments(task *ScanTask, collectionObject *cb_sdk.CollectionObject) {
+
+	if task.req.ContextClosed() {
+		return
+	}
+
+	existingOffsets, err := retraceExistingOffsets(task.req, task.CollectionIdentifier())
+	if err != nil {
+		task.Result.ErrorOther = err.Error()
+		task.Result.Failure++
+		return
+	}
+	expectedOffset := existingOffsets[keySpaceKey(task.OperationConfig)]
+
+	var docIds []string
+	startTime := time.Now()
+	initTime := startTime.UTC().Format(time.RFC850)
+	for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.Attempt(retry, err, start); retry++ {
+		startTime = time.Now()
+		initTime = startTime.UTC().Format(time.RFC850)
+		docIds, err = task.runScan(collectionObject)
+		if err == nil {
+			break
+		}
+	}
+	duration := time.Since(startTime)
+
+	if err != nil {
+		task.Result.ErrorOther = err.Error()
+		task.Result.Failure++
+		task.PostTaskExceptionHandling(collectionObject)
+		return
+	}
+
+	start, end := task.scanRange()
+	scanResult := &task_result.ScanResult{
+		ScanType:      task.ScanOptions.ScanType,
+		ItemsReturned: int64(len(docIds)),
+		DurationInMs:  duration.Milliseconds(),
+	}
+	if duration > 0 {
+		scanResult.ItemsPerSecond = float64(len(docIds)) / duration.Seconds()
+	}
+
+	returned := make(map[int64]struct{})
+	for _, docId := range docIds {
+		offset, ok := offsetFromKey(task.gen, task.MetaData.Seed, docId)
+		if !ok || offset < start || offset >= end {
+			continue
+		}
+		scanResult.ItemsInKeySpace++
+		returned[offset] = struct{}{}
+		if _, ok := expectedOffset[offset]; !ok {
+			scanResult.UnexpectedItems++
+			task.Result.IncrementFailure(initTime, docId, task_errors.ErrScanUnexpectedKey, false, 0, offset)
+		}
+	}
+
+	if task.ScanOptions.ScanType == cb_sdk.ScanTypeRange {
+		for offset := start; offset < end; offset++ {
+			if _, ok := expectedOffset[offset]; !ok {
+				continue
+			}
+			scanResult.ExpectedItems++
+			if _, ok := returned[offset]; !ok {
+				scanResult.MissingItems++
+				task.Result.IncrementFailure(initTime, task.gen.BuildKey(task.MetaData.Seed+offset),
+					task_errors.ErrScanMissingKey, false, 0, offset)
+			}
+		}
+	}
+
+	task.Result.ScanResult = scanResult
+	task.PostTaskExceptionHandling(collectionObject)
+	task.Result.Failure = scanResult.MissingItems + scanResult.UnexpectedItems
+	task.Result.Success = scanResult.ItemsInKeySpace - scanResult.UnexpectedItems
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+}
+
+func (task *ScanTask) PostTaskExceptionHandling(collectionObject *cb_sdk.CollectionObject) {
+	task.Result.StopStoringResult()
+	task.State.StopStoringState()
+}
+
+func (task *ScanTask) MatchResultSeed(resultSeed string) (bool, error) {
+	defer task.lock.Unlock()
+	task.lock.Lock()
+	if fmt.Sprintf("%d", task.ResultSeed) == resultSeed {
+		if task.TaskPending {
+			return true, task_errors.ErrTaskInPendingState
+		}
+		if task.Result == nil {
+			task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (task *ScanTask) GetCollectionObject() (*cb_sdk.CollectionObject, error) {
+	return task.req.GetCollection(task.ClusterConfig, task.Bucket, task.Scope,
+		task.Collection)
+}
+
+func (task *ScanTask) SetException(exceptions Exceptions) {
+	task.OperationConfig.Exceptions = exceptions
+}
+
+func (task *ScanTask) GetOperationConfig() (*OperationConfig, *task_state.TaskState) {
+	return nil, task.State
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/jaswdr/faker"
 	"golang.org/x/sync/errgroup"
 	"log"
-	"math"
 	"math/rand"
 	"strings"
 	"sync"
@@ -157,6 +156,7 @@ func (task *SubDocDelete) Do() error {
 		return task.TearUp()
 	}
 
+	task.Result.SetKeyLocator(collectionObject, task.OperationConfig.NodeSampleRate)
 	deleteSubDocuments(task, collectionObject)
 	task.Result.Success = (task.OperationConfig.End - task.OperationConfig.Start) - task.Result.Failure
 
@@ -204,8 +204,7 @@ func deleteSubDocuments(task *SubDocDelete, collectionObject *cb_sdk.CollectionO
 
 			var err error
 			initTime := time.Now().UTC().Format(time.RFC850)
-			for retry := 0; retry < int(math.Max(float64(1), float64(task.OperationConfig.Exceptions.
-				RetryAttempts))); retry++ {
+			for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.Attempt(retry, err, start); retry++ {
 
 				var iOps []gocb.MutateInSpec
 				for path, _ := range task.gen.Template.GenerateSubPathAndValue(&fake, task.OperationConfig.DocSize) {
@@ -223,17 +222,19 @@ func deleteSubDocuments(task *SubDocDelete, collectionObject *cb_sdk.CollectionO
 				}
 
 				initTime = time.Now().UTC().Format(time.RFC850)
-				_, err = collectionObject.Collection.MutateIn(docId, iOps, &gocb.MutateInOptions{
+				var result *gocb.MutateInResult
+				result, err = collectionObject.Collection.MutateIn(docId, iOps, &gocb.MutateInOptions{
 					Expiry:          time.Duration(task.MutateInOptions.Expiry) * time.Second,
 					PersistTo:       task.MutateInOptions.PersistTo,
 					ReplicateTo:     task.MutateInOptions.ReplicateTo,
 					DurabilityLevel: cb_sdk.GetDurability(task.MutateInOptions.Durability),
 					StoreSemantic:   cb_sdk.GetStoreSemantic(task.MutateInOptions.StoreSemantic),
-					Timeout:         time.Duration(task.MutateInOptions.Expiry) * time.Second,
+					Timeout:         time.Duration(task.MutateInOptions.Timeout) * time.Second,
 					PreserveExpiry:  task.MutateInOptions.PreserveExpiry,
 				})
 
 				if err == nil {
+					collectionObject.RecordMutation(&result.MutationResult)
 					break
 				}
 			}
@@ -246,6 +247,7 @@ func deleteSubDocuments(task *SubDocDelete, collectionObject *cb_sdk.CollectionO
 
 			}
 
+			task.Result.RecordSuccess(docId)
 			task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
 			<-routineLimiter
 			return nil
@@ -308,7 +310,7 @@ func (task *SubDocDelete) PostTaskExceptionHandling(collectionObject *cb_sdk.Col
 					var err error
 					result := &gocb.MutateInResult{}
 					initTime := time.Now().UTC().Format(time.RFC850)
-					for retry = 0; retry <= task.OperationConfig.Exceptions.RetryAttempts; retry++ {
+					for start := time.Now(); task.OperationConfig.Exceptions.RetryAttempt(retry, err, start); retry++ {
 
 						var iOps []gocb.MutateInSpec
 						for path, _ := range task.gen.Template.GenerateSubPathAndValue(&fake, task.OperationConfig.DocSize) {
@@ -332,11 +334,12 @@ func (task *SubDocDelete) PostTaskExceptionHandling(collectionObject *cb_sdk.Col
 							ReplicateTo:     task.MutateInOptions.ReplicateTo,
 							DurabilityLevel: cb_sdk.GetDurability(task.MutateInOptions.Durability),
 							StoreSemantic:   cb_sdk.GetStoreSemantic(task.MutateInOptions.StoreSemantic),
-							Timeout:         time.Duration(task.MutateInOptions.Expiry) * time.Second,
+							Timeout:         time.Duration(task.MutateInOptions.Timeout) * time.Second,
 							PreserveExpiry:  task.MutateInOptions.PreserveExpiry,
 						})
 
 						if err == nil {
+							collectionObject.RecordMutation(&result.MutationResult)
 							break
 						}
 					}
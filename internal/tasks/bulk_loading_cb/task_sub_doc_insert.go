@@ -15,7 +15,6 @@ import (
 	"github.com/jaswdr/faker"
 	"golang.org/x/sync/errgroup"
 	"log"
-	"math"
 	"math/rand"
 	"strings"
 	"sync"
@@ -162,6 +161,7 @@ func (task *SubDocInsert) Do() error {
 		return task.TearUp()
 	}
 
+	task.Result.SetKeyLocator(collectionObject, task.OperationConfig.NodeSampleRate)
 	insertSubDocuments(task, collectionObject)
 	task.Result.Success = (task.OperationConfig.End - task.OperationConfig.Start) - task.Result.Failure
 
@@ -209,8 +209,7 @@ func insertSubDocuments(task *SubDocInsert, collectionObject *cb_sdk.CollectionO
 
 			var err error
 			initTime := time.Now().UTC().Format(time.RFC850)
-			for retry := 0; retry < int(math.Max(float64(1), float64(task.OperationConfig.Exceptions.
-				RetryAttempts))); retry++ {
+			for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.Attempt(retry, err, start); retry++ {
 
 				var iOps []gocb.MutateInSpec
 				for path, value := range task.gen.Template.GenerateSubPathAndValue(&fake, task.OperationConfig.DocSize) {
@@ -229,17 +228,19 @@ func insertSubDocuments(task *SubDocInsert, collectionObject *cb_sdk.CollectionO
 				}
 
 				initTime = time.Now().UTC().Format(time.RFC850)
-				_, err = collectionObject.Collection.MutateIn(docId, iOps, &gocb.MutateInOptions{
+				var result *gocb.MutateInResult
+				result, err = collectionObject.Collection.MutateIn(docId, iOps, &gocb.MutateInOptions{
 					Expiry:          time.Duration(task.MutateInOptions.Expiry) * time.Second,
 					PersistTo:       task.MutateInOptions.PersistTo,
 					ReplicateTo:     task.MutateInOptions.ReplicateTo,
 					DurabilityLevel: cb_sdk.GetDurability(task.MutateInOptions.Durability),
 					StoreSemantic:   cb_sdk.GetStoreSemantic(task.MutateInOptions.StoreSemantic),
-					Timeout:         time.Duration(task.MutateInOptions.Expiry) * time.Second,
+					Timeout:         time.Duration(task.MutateInOptions.Timeout) * time.Second,
 					PreserveExpiry:  task.MutateInOptions.PreserveExpiry,
 				})
 
 				if err == nil {
+					collectionObject.RecordMutation(&result.MutationResult)
 					break
 				}
 			}
@@ -256,6 +257,7 @@ func insertSubDocuments(task *SubDocInsert, collectionObject *cb_sdk.CollectionO
 				}
 			}
 
+			task.Result.RecordSuccess(docId)
 			task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
 			<-routineLimiter
 			return nil
@@ -318,7 +320,7 @@ func (task *SubDocInsert) PostTaskExceptionHandling(collectionObject *cb_sdk.Col
 
 					result := &gocb.MutateInResult{}
 					initTime := time.Now().UTC().Format(time.RFC850)
-					for retry = 0; retry <= task.OperationConfig.Exceptions.RetryAttempts; retry++ {
+					for start := time.Now(); task.OperationConfig.Exceptions.RetryAttempt(retry, err, start); retry++ {
 
 						var iOps []gocb.MutateInSpec
 						for path, value := range task.gen.Template.GenerateSubPathAndValue(&fake,
@@ -344,11 +346,12 @@ func (task *SubDocInsert) PostTaskExceptionHandling(collectionObject *cb_sdk.Col
 							ReplicateTo:     task.MutateInOptions.ReplicateTo,
 							DurabilityLevel: cb_sdk.GetDurability(task.MutateInOptions.Durability),
 							StoreSemantic:   cb_sdk.GetStoreSemantic(task.MutateInOptions.StoreSemantic),
-							Timeout:         time.Duration(task.MutateInOptions.Expiry) * time.Second,
+							Timeout:         time.Duration(task.MutateInOptions.Timeout) * time.Second,
 							PreserveExpiry:  task.MutateInOptions.PreserveExpiry,
 						})
 
 						if err == nil {
+							collectionObject.RecordMutation(&result.MutationResult)
 							break
 						}
 					}
@@ -14,7 +14,6 @@ import (
 	"github.com/jaswdr/faker"
 	"golang.org/x/sync/errgroup"
 	"log"
-	"math"
 	"math/rand"
 	"strings"
 	"sync"
@@ -146,6 +145,7 @@ func (task *SubDocRead) Do() error {
 		return task.TearUp()
 	}
 
+	task.Result.SetKeyLocator(collectionObject, task.OperationConfig.NodeSampleRate)
 	readSubDocuments(task, collectionObject)
 	task.Result.Success = (task.OperationConfig.End - task.OperationConfig.Start) - task.Result.Failure
 
@@ -196,8 +196,7 @@ func readSubDocuments(task *SubDocRead, collectionObject *cb_sdk.CollectionObjec
 			result := &gocb.LookupInResult{}
 			var paths []string
 			initTime := time.Now().UTC().Format(time.RFC850)
-			for retry := 0; retry < int(math.Max(float64(1), float64(task.OperationConfig.Exceptions.
-				RetryAttempts))); retry++ {
+			for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.Attempt(retry, err, start); retry++ {
 
 				var iOps []gocb.LookupInSpec
 				for path, _ := range task.gen.Template.GenerateSubPathAndValue(&fake, task.OperationConfig.DocSize) {
@@ -233,6 +232,7 @@ func readSubDocuments(task *SubDocRead, collectionObject *cb_sdk.CollectionObjec
 				}
 			}
 
+			task.Result.RecordSuccess(docId)
 			task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
 			<-routineLimiter
 			return nil
@@ -297,7 +297,7 @@ func (task *SubDocRead) PostTaskExceptionHandling(collectionObject *cb_sdk.Colle
 					result := &gocb.LookupInResult{}
 					var paths []string
 
-					for retry = 0; retry <= task.OperationConfig.Exceptions.RetryAttempts; retry++ {
+					for start := time.Now(); task.OperationConfig.Exceptions.RetryAttempt(retry, err, start); retry++ {
 
 						var iOps []gocb.LookupInSpec
 						for path, _ := range task.gen.Template.GenerateSubPathAndValue(&fake,
@@ -14,7 +14,6 @@ import (
 	"github.com/jaswdr/faker"
 	"golang.org/x/sync/errgroup"
 	"log"
-	"math"
 	"math/rand"
 	"strings"
 	"sync"
@@ -160,6 +159,7 @@ func (task *SubDocUpsert) Do() error {
 		return task.TearUp()
 	}
 
+	task.Result.SetKeyLocator(collectionObject, task.OperationConfig.NodeSampleRate)
 	upsertSubDocuments(task, collectionObject)
 	task.Result.Success = (task.OperationConfig.End - task.OperationConfig.Start) - task.Result.Failure
 
@@ -211,8 +211,7 @@ func upsertSubDocuments(task *SubDocUpsert, collectionObject *cb_sdk.CollectionO
 
 			var err error
 			initTime := time.Now().UTC().Format(time.RFC850)
-			for retry := 0; retry < int(math.Max(float64(1), float64(task.OperationConfig.Exceptions.
-				RetryAttempts))); retry++ {
+			for retry, start := 0, time.Now(); task.OperationConfig.Exceptions.Attempt(retry, err, start); retry++ {
 
 				var iOps []gocb.MutateInSpec
 				for path, value := range task.gen.Template.GenerateSubPathAndValue(&fake, task.OperationConfig.DocSize) {
@@ -231,17 +230,23 @@ func upsertSubDocuments(task *SubDocUpsert, collectionObject *cb_sdk.CollectionO
 				}
 
 				initTime = time.Now().UTC().Format(time.RFC850)
-				_, err = collectionObject.Collection.MutateIn(docId, iOps, &gocb.MutateInOptions{
+				var result *gocb.MutateInResult
+				result, err = collectionObject.Collection.MutateIn(docId, iOps, &gocb.MutateInOptions{
 					Expiry:          time.Duration(task.MutateInOptions.Expiry) * time.Second,
 					PersistTo:       task.MutateInOptions.PersistTo,
 					ReplicateTo:     task.MutateInOptions.ReplicateTo,
 					DurabilityLevel: cb_sdk.GetDurability(task.MutateInOptions.Durability),
 					StoreSemantic:   cb_sdk.GetStoreSemantic(task.MutateInOptions.StoreSemantic),
-					Timeout:         time.Duration(task.MutateInOptions.Expiry) * time.Second,
+					Timeout:         time.Duration(task.MutateInOptions.Timeout) * time.Second,
 					PreserveExpiry:  task.MutateInOptions.PreserveExpiry,
 				})
 
 				if err == nil {
+					collectionObject.RecordMutation(&result.MutationResult)
+					break
+				}
+				if cb_sdk.IsAmbiguousError(err) {
+					// the mutation is not idempotent and may have been applied.
 					break
 				}
 			}
@@ -253,6 +258,7 @@ func upsertSubDocuments(task *SubDocUpsert, collectionObject *cb_sdk.CollectionO
 
 			}
 
+			task.Result.RecordSuccess(docId)
 			task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
 			<-routineLimiter
 			return nil
@@ -320,7 +326,7 @@ func (task *SubDocUpsert) PostTaskExceptionHandling(collectionObject *cb_sdk.Col
 					var err error
 					result := &gocb.MutateInResult{}
 
-					for retry = 0; retry <= task.OperationConfig.Exceptions.RetryAttempts; retry++ {
+					for start := time.Now(); task.OperationConfig.Exceptions.RetryAttempt(retry, err, start); retry++ {
 
 						var iOps []gocb.MutateInSpec
 						for path, value := range task.gen.Template.GenerateSubPathAndValue(&fake,
@@ -345,11 +351,16 @@ func (task *SubDocUpsert) PostTaskExceptionHandling(collectionObject *cb_sdk.Col
 							ReplicateTo:     task.MutateInOptions.ReplicateTo,
 							DurabilityLevel: cb_sdk.GetDurability(task.MutateInOptions.Durability),
 							StoreSemantic:   cb_sdk.GetStoreSemantic(task.MutateInOptions.StoreSemantic),
-							Timeout:         time.Duration(task.MutateInOptions.Expiry) * time.Second,
+							Timeout:         time.Duration(task.MutateInOptions.Timeout) * time.Second,
 							PreserveExpiry:  task.MutateInOptions.PreserveExpiry,
 						})
 
 						if err == nil {
+							collectionObject.RecordMutation(&result.MutationResult)
+							break
+						}
+						if cb_sdk.IsAmbiguousError(err) {
+							// the mutation is not idempotent and may have been applied.
 							break
 						}
 					}
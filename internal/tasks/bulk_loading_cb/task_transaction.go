@@ -0,0 +1,405 @@
+package bulk_loading_cb
+
+import (
+	"errors"
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/docgenerator"
+	"github.com/couchbaselabs/sirius/internal/meta_data"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/task_state"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/couchbaselabs/sirius/internal/template"
+	"github.com/jaswdr/faker"
+	"golang.org/x/sync/errgroup"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	transactionInsert  = "insert"
+	transactionReplace = "replace"
+	transactionRemove  = "remove"
+)
+
+type TransactionTask struct {
+	IdentifierToken    string                        `json:"identifierToken" doc:"true"`
+	ClusterConfig      *cb_sdk.ClusterConfig         `json:"clusterConfig" doc:"true"`
+	Bucket             string                        `json:"bucket" doc:"true"`
+	Scope              string                        `json:"scope,omitempty" doc:"true"`
+	Collection         string                        `json:"collection,omitempty" doc:"true"`
+	TransactionOptions *cb_sdk.TransactionOptions    `json:"transactionOptions,omitempty" doc:"true"`
+	OperationConfig    *OperationConfig              `json:"operationConfig,omitempty" doc:"true"`
+	Operation          string                        `json:"operation" doc:"false"`
+	ResultSeed         int64                         `json:"resultSeed" doc:"false"`
+	TaskPending        bool                          `json:"taskPending" doc:"false"`
+	InsertFrom         int64                         `json:"insertFrom" doc:"false"`
+	State              *task_state.TaskState         `json:"State" doc:"false"`
+	MetaData           *meta_data.CollectionMetaData `json:"metaData" doc:"false"`
+	Result             *task_result.TaskResult       `json:"-" doc:"false"`
+	gen                *docgenerator.Generator       `json:"-" doc:"false"`
+	req                *tasks.Request                `json:"-" doc:"false"`
+	rerun              bool                          `json:"-" doc:"false"`
+	lock               sync.Mutex                    `json:"-" doc:"false"`
+}
+
+func (task *TransactionTask) Describe() string {
+	return `Transaction task runs multi document ACID transactions on the documents ranging from [start,end).
+Documents are grouped into transactions of docsPerTransaction consecutive offsets. Offsets already loaded by
+Sirius are replaced (updating fieldsToChange) or removed as per removeRatio, offsets beyond the loaded key space
+are inserted. A transaction is rolled back instead of committed as per rollbackRatio. Only committed
+transactions are considered while validating documents. Committed, rolled back, ambiguous and failed
+transactions along with per attempt timings are reported in the task result.`
+}
+
+func (task *TransactionTask) CollectionIdentifier() string {
+	clusterIdentifier, _ := cb_sdk.GetClusterIdentifier(task.ClusterConfig.ConnectionString)
+	return strings.Join([]string{task.IdentifierToken, clusterIdentifier, task.Bucket, task.Scope,
+		task.Collection}, ":")
+}
+
+func (task *TransactionTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+func (task *TransactionTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	task.lock = sync.Mutex{}
+	task.rerun = reRun
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+		task.Operation = tasks.TransactionOperation
+
+		if task.Bucket == "" {
+			task.Bucket = cb_sdk.DefaultBucket
+		}
+		if task.Scope == "" {
+			task.Scope = cb_sdk.DefaultScope
+		}
+		if task.Collection == "" {
+			task.Collection = cb_sdk.DefaultCollection
+		}
+
+		if err := cb_sdk.ConfigTransactionOptions(task.TransactionOptions); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+		if err := ConfigureOperationConfig(task.OperationConfig); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+		task.MetaData = task.req.MetaData.GetCollectionMetadata(task.CollectionIdentifier())
+
+		task.req.Lock()
+		task.InsertFrom = task.MetaData.SeedEnd - task.MetaData.Seed
+		if task.OperationConfig.End+task.MetaData.Seed > task.MetaData.SeedEnd {
+			task.req.AddToSeedEnd(task.MetaData, (task.OperationConfig.End+task.MetaData.Seed)-(task.MetaData.SeedEnd))
+		}
+		task.State = task_state.ConfigTaskState(task.MetaData.Seed, task.MetaData.SeedEnd, task.ResultSeed)
+		task.req.Unlock()
+
+	} else {
+		if task.State == nil {
+			return task.ResultSeed, task_errors.ErrTaskStateIsNil
+		}
+
+		task.State.SetupStoringKeys()
+		_ = task_result.DeleteResultFile(task.ResultSeed)
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+	return task.ResultSeed, nil
+}
+
+func (task *TransactionTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed, task.Operation)
+	}
+	task.Result = nil
+	task.State.StopStoringState()
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *TransactionTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	collectionObject, err1 := task.GetCollectionObject()
+
+	task.gen = docgenerator.ConfigGenerator(
+		task.OperationConfig.KeySize,
+		task.OperationConfig.DocSize,
+		task.OperationConfig.DocType,
+		task.OperationConfig.KeyPrefix,
+		task.OperationConfig.KeySuffix,
+		template.InitialiseTemplate(task.OperationConfig.TemplateName))
+
+	if err1 != nil {
+		task.Result.ErrorOther = err1.Error()
+		task.Result.FailWholeBulkOperation(task.OperationConfig.Start, task.OperationConfig.End,
+			err1, task.State, task.gen, task.MetaData.Seed)
+		return task.TearUp()
+	}
+
+	cluster, err := task.req.GetCluster(task.ClusterConfig)
+	if err != nil {
+		task.Result.ErrorOther = err.Error()
+		task.Result.FailWholeBulkOperation(task.OperationConfig.Start, task.OperationConfig.End,
+			err, task.State, task.gen, task.MetaData.Seed)
+		return task.TearUp()
+	}
+
+	runTransactions(task, cluster, collectionObject)
+
+	return task.TearUp()
+}
+
+// operationForOffset returns the operation performed on the offset within its transaction. It is derived from
+// the result seed so that the operation can be retraced while validating.
+func (task *TransactionTask) operationForOffset(offset int64) string {
+	if offset >= task.InsertFrom {
+		return transactionInsert
+	}
+	if rand.New(rand.NewSource(task.ResultSeed+offset)).Float64() < task.TransactionOptions.RemoveRatio {
+		return transactionRemove
+	}
+	return transactionReplace
+}
+
+// rollbackTransaction returns true if the transaction starting at the offset has to be rolled back.
+func (task *TransactionTask) rollbackTransaction(offset int64) bool {
+	return rand.New(rand.NewSource(task.ResultSeed-offset)).Float64() < task.TransactionOptions.RollbackRatio
+}
+
+// buildDocument returns the document written for an offset by an insert or replace.
+func (task *TransactionTask) buildDocument(offset int64, operation string) (interface{}, error) {
+	key := task.MetaData.Seed + offset
+	fake := faker.NewWithSeed(rand.NewSource(int64(key)))
+	doc, err := task.gen.Template.GenerateDocument(&fake, task.OperationConfig.DocSize)
+	if err != nil || operation == transactionInsert {
+		return doc, err
+	}
+	doc, err = retracePreviousMutations(task.req, task.CollectionIdentifier(), offset, doc, *task.gen, &fake,
+		task.ResultSeed)
+	if err != nil {
+		return nil, err
+	}
+	return task.gen.Template.UpdateDocument(task.OperationConfig.FieldsToChange, doc,
+		task.OperationConfig.DocSize, &fake)
+}
+
+// runTransaction stages the operations on the offsets of a transaction and commits or rolls it back. It
+// returns the outcome of the transaction, the time taken by each attempt and the error if any.
+func (task *TransactionTask) runTransaction(cluster *gocb.Cluster, collectionObject *cb_sdk.CollectionObject,
+	offsets []int64) (string, []time.Duration, error) {
+
+	docs := make([]interface{}, len(offsets))
+	for i, offset := range offsets {
+		if operation := task.operationForOffset(offset); operation != transactionRemove {
+			doc, err := task.buildDocument(offset, operation)
+			if err != nil {
+				return task_result.TransactionFailed, nil, err
+			}
+			docs[i] = doc
+		}
+	}
+
+	rollback := task.rollbackTransaction(offsets[0])
+	rolledBack := false
+	var attemptStarts []time.Time
+
+	_, err := cluster.Transactions().Run(func(ctx *gocb.TransactionAttemptContext) error {
+		attemptStarts = append(attemptStarts, time.Now())
+		for i, offset := range offsets {
+			docId := task.gen.BuildKey(task.MetaData.Seed + offset)
+			switch task.operationForOffset(offset) {
+			case transactionInsert:
+				if _, err := ctx.Insert(collectionObject.Collection, docId, docs[i]); err != nil {
+					return err
+				}
+			case transactionReplace:
+				doc, err := ctx.Get(collectionObject.Collection, docId)
+				if err != nil {
+					return err
+				}
+				if _, err := ctx.Replace(doc, docs[i]); err != nil {
+					return err
+				}
+			case transactionRemove:
+				doc, err := ctx.Get(collectionObject.Collection, docId)
+				if err != nil {
+					return err
+				}
+				if err := ctx.Remove(doc); err != nil {
+					return err
+				}
+			}
+		}
+		if rollback {
+			rolledBack = true
+			return task_errors.ErrTransactionRolledBack
+		}
+		return nil
+	}, &gocb.TransactionOptions{
+		DurabilityLevel: cb_sdk.GetDurability(task.TransactionOptions.Durability),
+		Timeout:         time.Duration(task.TransactionOptions.Timeout) * time.Second,
+	})
+
+	endTime := time.Now()
+	attemptTimes := make([]time.Duration, len(attemptStarts))
+	for i := range attemptStarts {
+		if i+1 < len(attemptStarts) {
+			attemptTimes[i] = attemptStarts[i+1].Sub(attemptStarts[i])
+		} else {
+			attemptTimes[i] = endTime.Sub(attemptStarts[i])
+		}
+	}
+
+	var postCommitErr gocb.TransactionFailedPostCommit
+	var ambiguousErr gocb.TransactionCommitAmbiguousError
+	switch {
+	case err == nil || errors.As(err, &postCommitErr):
+		return task_result.TransactionCommitted, attemptTimes, nil
+	case rolledBack:
+		return task_result.TransactionRolledBack, attemptTimes, err
+	case errors.As(err, &ambiguousErr):
+		return task_result.TransactionAmbiguous, attemptTimes, err
+	default:
+		return task_result.TransactionFailed, attemptTimes, err
+	}
+}
+
+// runTransactions groups the offsets into transactions and runs them concurrently.
+func runTransactions(task *TransactionTask, cluster *gocb.Cluster, collectionObject *cb_sdk.CollectionObject) {
+
+	if task.req.ContextClosed() {
+		return
+	}
+
+	routineLimiter := make(chan struct{}, tasks.MaxConcurrentRoutines)
+	dataChannel := make(chan int64, tasks.MaxConcurrentRoutines)
+
+	skip := make(map[int64]struct{})
+	for _, offset := range task.State.KeyStates.Completed {
+		skip[offset] = struct{}{}
+	}
+	for _, offset := range task.State.KeyStates.Err {
+		skip[offset] = struct{}{}
+	}
+
+	var committedDocs int64
+	docsPerTransaction := int64(task.TransactionOptions.DocsPerTransaction)
+
+	group := errgroup.Group{}
+	for i := task.OperationConfig.Start; i < task.OperationConfig.End; i += docsPerTransaction {
+
+		if task.req.ContextClosed() {
+			close(routineLimiter)
+			close(dataChannel)
+			return
+		}
+
+		routineLimiter <- struct{}{}
+		dataChannel <- i
+		group.Go(func() error {
+			start := <-dataChannel
+			if _, ok := skip[start]; ok {
+				<-routineLimiter
+				return fmt.Errorf("alreday performed transaction on offset %d", start)
+			}
+
+			var offsets []int64
+			for offset := start; offset < start+docsPerTransaction && offset < task.OperationConfig.End; offset++ {
+				offsets = append(offsets, offset)
+			}
+
+			initTime := time.Now().UTC().Format(time.RFC850)
+			startTime := time.Now()
+			outcome, attemptTimes, err := task.runTransaction(cluster, collectionObject, offsets)
+			task.Result.RecordTransaction(outcome, attemptTimes, time.Since(startTime))
+
+			switch outcome {
+			case task_result.TransactionCommitted:
+				atomic.AddInt64(&committedDocs, int64(len(offsets)))
+				for _, offset := range offsets {
+					task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
+				}
+			case task_result.TransactionRolledBack:
+				// rolled back offsets are neither completed nor failed, retracing ignores them.
+			default:
+				for _, offset := range offsets {
+					task.Result.IncrementFailure(initTime, task.gen.BuildKey(task.MetaData.Seed+offset), err,
+						false, 0, offset)
+					task.State.StateChannel <- task_state.StateHelper{Status: task_state.ERR, Offset: offset}
+				}
+			}
+
+			<-routineLimiter
+			return err
+		})
+	}
+	_ = group.Wait()
+	close(routineLimiter)
+	close(dataChannel)
+	task.PostTaskExceptionHandling(collectionObject)
+	task.Result.Success = committedDocs
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+}
+
+// PostTaskExceptionHandling doesn't retry failed offsets individually as they have to be retried as a whole
+// transaction, which is already done by the SDK.
+func (task *TransactionTask) PostTaskExceptionHandling(collectionObject *cb_sdk.CollectionObject) {
+	task.Result.StopStoringResult()
+	task.State.StopStoringState()
+}
+
+func (task *TransactionTask) MatchResultSeed(resultSeed string) (bool, error) {
+	defer task.lock.Unlock()
+	task.lock.Lock()
+	if fmt.Sprintf("%d", task.ResultSeed) == resultSeed {
+		if task.TaskPending {
+			return true, task_errors.ErrTaskInPendingState
+		}
+		if task.Result == nil {
+			task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (task *TransactionTask) GetCollectionObject() (*cb_sdk.CollectionObject, error) {
+	return task.req.GetCollection(task.ClusterConfig, task.Bucket, task.Scope,
+		task.Collection)
+}
+
+func (task *TransactionTask) SetException(exceptions Exceptions) {
+	task.OperationConfig.Exceptions = exceptions
+}
+
+func (task *TransactionTask) GetOperationConfig() (*OperationConfig, *task_state.TaskState) {
+	return task.OperationConfig, task.State
+}
@@ -16,10 +16,11 @@ import (
 	"github.com/jaswdr/faker"
 	"golang.org/x/sync/errgroup"
 	"log"
-	"math"
 	"math/rand"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -38,6 +39,8 @@ type ValidateTask struct {
 	gen             *docgenerator.Generator       `json:"-" doc:"false"`
 	req             *tasks.Request                `json:"-" doc:"false"`
 	rerun           bool                          `json:"-" doc:"false"`
+	counterChecks   int64                         `json:"-" doc:"false"`
+	skippedOffsets  int64                         `json:"-" doc:"false"`
 	lock            sync.Mutex                    `json:"–" doc:"false"`
 }
 
@@ -48,7 +51,9 @@ func (task *ValidateTask) CollectionIdentifier() string {
 }
 
 func (task *ValidateTask) Describe() string {
-	return "Validates every document in the cluster's bucket"
+	return `Validates every document and counter in the cluster's bucket.
+Offsets on which no document was written, as the ones only used by counter or binary operations, are not
+validated as documents and are not counted in the success of the task.`
 }
 
 func (task *ValidateTask) CheckIfPending() bool {
@@ -135,9 +140,11 @@ func (task *ValidateTask) Do() error {
 		return task.TearUp()
 	}
 
+	task.skippedOffsets, task.counterChecks = 0, 0
 	validateDocuments(task, collectionObject)
 
-	task.Result.Success = task.State.SeedEnd - task.State.SeedStart - task.Result.Failure
+	task.Result.Success = task.State.SeedEnd - task.State.SeedStart - task.skippedOffsets + task.counterChecks -
+		task.Result.Failure
 
 	return task.TearUp()
 }
@@ -184,15 +191,20 @@ func validateDocuments(task *ValidateTask, collectionObject *cb_sdk.CollectionOb
 		group.Go(func() error {
 			offset := <-dataChannel
 
-			if _, ok := skip[offset]; ok {
+			// offsets without a document write, as the ones of counter key spaces, are not validated here.
+			operationConfigDoc, err := retrieveLastConfig(task.req, offset, false)
+			if err != nil {
+				atomic.AddInt64(&task.skippedOffsets, 1)
+				if _, ok := skip[offset]; !ok {
+					task.State.StateChannel <- task_state.StateHelper{Status: task_state.COMPLETED, Offset: offset}
+				}
 				<-routineLimiter
 				return nil
 			}
 
-			operationConfigDoc, err := retrieveLastConfig(task.req, offset, false)
-			if err != nil {
+			if _, ok := skip[offset]; ok {
 				<-routineLimiter
-				return err
+				return nil
 			}
 			operationConfigSubDoc, err := retrieveLastConfig(task.req, offset, true)
 
@@ -285,8 +297,7 @@ func validateDocuments(task *ValidateTask, collectionObject *cb_sdk.CollectionOb
 			resultFromHost := make(map[string]any)
 
 			initTime = time.Now().UTC().Format(time.RFC850)
-			for retry := 0; retry < int(math.Max(float64(1), float64(operationConfigDoc.Exceptions.
-				RetryAttempts))); retry++ {
+			for retry, start := 0, time.Now(); operationConfigDoc.Exceptions.Attempt(retry, err, start); retry++ {
 				result, err = collectionObject.Collection.Get(docId, nil)
 				if err == nil {
 					break
@@ -336,11 +347,98 @@ func validateDocuments(task *ValidateTask, collectionObject *cb_sdk.CollectionOb
 	_ = group.Wait()
 	close(routineLimiter)
 	close(dataChannel)
+	validateCounters(task, collectionObject)
 	task.PostTaskExceptionHandling(collectionObject)
 	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
 
 }
 
+// validateCounters compares every counter expected to exist with the value retraced from increment and decrement
+// operations.
+func validateCounters(task *ValidateTask, collectionObject *cb_sdk.CollectionObject) {
+	keySpaces, err := retraceCounterKeySpaces(task.req, task.CollectionIdentifier())
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	routineLimiter := make(chan struct{}, tasks.MaxConcurrentRoutines)
+	dataChannel := make(chan int64, tasks.MaxConcurrentRoutines)
+	group := errgroup.Group{}
+
+	for i := range keySpaces {
+		keySpace := keySpaces[i]
+		gen := docgenerator.ConfigGenerator(keySpace.KeySize, keySpace.DocSize, keySpace.DocType, keySpace.KeyPrefix,
+			keySpace.KeySuffix, template.InitialiseTemplate(keySpace.TemplateName))
+
+		for offset := keySpace.Start; offset < keySpace.End; offset++ {
+
+			if task.req.ContextClosed() {
+				_ = group.Wait()
+				return
+			}
+
+			routineLimiter <- struct{}{}
+			dataChannel <- offset
+			group.Go(func() error {
+				offset := <-dataChannel
+				docId := gen.BuildKey(task.MetaData.Seed + offset)
+				initTime := time.Now().UTC().Format(time.RFC850)
+
+				expected, exists, err := retracePreviousCounterMutations(task.req, task.CollectionIdentifier(),
+					offset, &keySpace, task.ResultSeed)
+				if err != nil {
+					atomic.AddInt64(&task.counterChecks, 1)
+					task.Result.IncrementFailure(initTime, docId, err, false, 0, offset)
+					<-routineLimiter
+					return err
+				}
+				if !exists {
+					<-routineLimiter
+					return nil
+				}
+				atomic.AddInt64(&task.counterChecks, 1)
+
+				result, err := collectionObject.Collection.Get(docId, &gocb.GetOptions{
+					Transcoder: gocb.NewRawBinaryTranscoder(),
+				})
+				if err != nil {
+					task.Result.IncrementFailure(initTime, docId, err, false, 0, offset)
+					<-routineLimiter
+					return err
+				}
+
+				var content []byte
+				if err := result.Content(&content); err != nil {
+					task.Result.IncrementFailure(initTime, docId, err, false, uint64(result.Cas()), offset)
+					<-routineLimiter
+					return err
+				}
+
+				value, err := strconv.ParseUint(string(content), 10, 64)
+				if err != nil {
+					task.Result.IncrementFailure(initTime, docId, err, false, uint64(result.Cas()), offset)
+					<-routineLimiter
+					return err
+				}
+
+				if value != expected {
+					err = fmt.Errorf("integrity Lost : expected counter %d, found %d", expected, value)
+					task.Result.IncrementFailure(initTime, docId, err, false, uint64(result.Cas()), offset)
+					<-routineLimiter
+					return err
+				}
+
+				<-routineLimiter
+				return nil
+			})
+		}
+	}
+	_ = group.Wait()
+	close(routineLimiter)
+	close(dataChannel)
+}
+
 func (task *ValidateTask) PostTaskExceptionHandling(collectionObject *cb_sdk.CollectionObject) {
 	task.Result.StopStoringResult()
 	task.State.StopStoringState()
@@ -0,0 +1,57 @@
+package bulk_query_cb
+
+import (
+	"encoding/json"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/couchbaselabs/sirius/internal/template"
+	"github.com/jaswdr/faker"
+	"math/rand"
+	"strings"
+)
+
+// documentFieldValue returns the value present at the dotted path of a document. For arrays the first element
+// is returned.
+func documentFieldValue(document map[string]interface{}, path string) (interface{}, bool) {
+	var value interface{} = document
+	for _, part := range strings.Split(path, ".") {
+		node, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = node[part]; !ok {
+			return nil, false
+		}
+	}
+	if values, ok := value.([]interface{}); ok {
+		if len(values) == 0 {
+			return nil, false
+		}
+		value = values[0]
+	}
+	return value, true
+}
+
+// sampleDocument returns the document generated for a random key of the key space loaded by Sirius in a
+// collection as a map.
+func sampleDocument(req *tasks.Request, collectionIdentifier string, t template.Template,
+	r *rand.Rand) (map[string]interface{}, error) {
+	collectionMetaData := req.MetaData.GetCollectionMetadata(collectionIdentifier)
+	key := collectionMetaData.Seed
+	if collectionMetaData.SeedEnd > collectionMetaData.Seed {
+		key += r.Int63n(collectionMetaData.SeedEnd - collectionMetaData.Seed)
+	}
+	fake := faker.NewWithSeed(rand.NewSource(key))
+	document, err := t.GenerateDocument(&fake, 0)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,276 @@
+package bulk_query_cb
+
+import (
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/couchbaselabs/sirius/internal/tasks/bulk_loading_cb"
+	"github.com/couchbaselabs/sirius/internal/template"
+	"golang.org/x/sync/errgroup"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var invalidAnalyticsNameCharacters = regexp.MustCompile(`[^0-9A-Za-z_]`)
+
+type AnalyticsTask struct {
+	IdentifierToken          string                           `json:"identifierToken" doc:"true"`
+	ClusterConfig            *cb_sdk.ClusterConfig            `json:"clusterConfig" doc:"true"`
+	Bucket                   string                           `json:"bucket" doc:"true"`
+	Scope                    string                           `json:"scope,omitempty" doc:"true"`
+	Collection               string                           `json:"collection,omitempty" doc:"true"`
+	AnalyticsOperationConfig *cb_sdk.AnalyticsOperationConfig `json:"operationConfig,omitempty" doc:"true"`
+	Template                 template.Template                `json:"-" doc:"false"`
+	Operation                string                           `json:"operation" doc:"false"`
+	ResultSeed               int64                            `json:"resultSeed" doc:"false"`
+	TaskPending              bool                             `json:"taskPending" doc:"false"`
+	CreateDatasets           bool                             `json:"createDatasets" doc:"false"`
+	Result                   *task_result.TaskResult          `json:"-" doc:"false"`
+	req                      *tasks.Request                   `json:"-" doc:"false"`
+}
+
+func (task *AnalyticsTask) Describe() string {
+	return `Analytics task creates analytics collections on the Local link over the collection and optionally a
+second collection of the same bucket (joinScope, joinCollection) loaded by Sirius, waits for both to ingest the
+items expected as per the previous operations and then runs template derived aggregation and join queries
+over a period of time. Latency and rows returned by every query are reported in the task result.`
+}
+
+func (task *AnalyticsTask) CollectionIdentifier() string {
+	return task.collectionIdentifier(task.Scope, task.Collection)
+}
+
+func (task *AnalyticsTask) collectionIdentifier(scope string, collection string) string {
+	clusterIdentifier, _ := cb_sdk.GetClusterIdentifier(task.ClusterConfig.ConnectionString)
+	return strings.Join([]string{task.IdentifierToken, clusterIdentifier, task.Bucket, scope,
+		collection}, ":")
+}
+
+func (task *AnalyticsTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+func (task *AnalyticsTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.CreateDatasets = false
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+		task.Operation = tasks.AnalyticsOperation
+		task.CreateDatasets = true
+
+		if task.Bucket == "" {
+			task.Bucket = cb_sdk.DefaultBucket
+		}
+		if task.Scope == "" {
+			task.Scope = cb_sdk.DefaultScope
+		}
+		if task.Collection == "" {
+			task.Collection = cb_sdk.DefaultCollection
+		}
+
+		if err := cb_sdk.ConfigAnalyticsOperationConfig(task.AnalyticsOperationConfig); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+	} else {
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+
+	task.Template = template.InitialiseTemplate(task.AnalyticsOperationConfig.Template)
+	return task.ResultSeed, nil
+}
+
+func (task *AnalyticsTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed)
+	}
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *AnalyticsTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	cluster, err := task.req.GetCluster(task.ClusterConfig)
+	if err != nil {
+		task.Result.ErrorOther = err.Error()
+		return task.TearUp()
+	}
+
+	datasets := map[string]string{
+		task.CollectionIdentifier(): task.datasetName(task.Scope, task.Collection),
+	}
+	if task.AnalyticsOperationConfig.JoinCollection != "" {
+		datasets[task.collectionIdentifier(task.AnalyticsOperationConfig.JoinScope,
+			task.AnalyticsOperationConfig.JoinCollection)] = task.datasetName(task.AnalyticsOperationConfig.JoinScope,
+			task.AnalyticsOperationConfig.JoinCollection)
+	}
+
+	if task.CreateDatasets {
+		createAnalyticsDatasets(task, cluster)
+		task.CreateDatasets = false
+	}
+
+	for collectionIdentifier, dataset := range datasets {
+		if err := waitForAnalyticsIngestion(task, cluster, collectionIdentifier, dataset); err != nil {
+			task.Result.ErrorOther = err.Error()
+			return task.TearUp()
+		}
+	}
+
+	runAnalyticsQueries(task, cluster)
+
+	return task.TearUp()
+}
+
+// datasetName returns the qualified name of the analytics collection created over a collection.
+func (task *AnalyticsTask) datasetName(scope string, collection string) string {
+	name := invalidAnalyticsNameCharacters.ReplaceAllString(strings.Join([]string{task.Bucket, scope, collection},
+		"_"), "_")
+	return fmt.Sprintf("`%s`.`%s`", task.AnalyticsOperationConfig.Dataverse, name)
+}
+
+// analyticsQuery runs an analytics statement and returns the number of rows returned by it.
+func analyticsQuery(task *AnalyticsTask, cluster *gocb.Cluster, statement string) (uint64, error) {
+	result, err := cluster.AnalyticsQuery(statement, &gocb.AnalyticsOptions{
+		Timeout: time.Duration(task.AnalyticsOperationConfig.Timeout) * time.Second,
+	})
+	if err != nil {
+		return 0, err
+	}
+	var rows uint64
+	for result.Next() {
+		rows++
+	}
+	if err := result.Close(); err != nil {
+		return rows, err
+	}
+	return rows, result.Err()
+}
+
+// createAnalyticsDatasets creates the dataverse and the analytics collections over the collections and connects
+// the Local link of the dataverse.
+func createAnalyticsDatasets(task *AnalyticsTask, cluster *gocb.Cluster) {
+	statements := []string{
+		fmt.Sprintf("CREATE DATAVERSE `%s` IF NOT EXISTS;", task.AnalyticsOperationConfig.Dataverse),
+		fmt.Sprintf("CREATE DATASET IF NOT EXISTS %s ON `%s`.`%s`.`%s`;", task.datasetName(task.Scope,
+			task.Collection), task.Bucket, task.Scope, task.Collection),
+	}
+	if task.AnalyticsOperationConfig.JoinCollection != "" {
+		statements = append(statements, fmt.Sprintf("CREATE DATASET IF NOT EXISTS %s ON `%s`.`%s`.`%s`;",
+			task.datasetName(task.AnalyticsOperationConfig.JoinScope, task.AnalyticsOperationConfig.JoinCollection),
+			task.Bucket, task.AnalyticsOperationConfig.JoinScope, task.AnalyticsOperationConfig.JoinCollection))
+	}
+	statements = append(statements, fmt.Sprintf("CONNECT LINK `%s`.Local;",
+		task.AnalyticsOperationConfig.Dataverse))
+
+	for _, statement := range statements {
+		if _, err := analyticsQuery(task, cluster, statement); err != nil {
+			task.Result.IncrementQueryFailure(statement, err)
+		}
+	}
+}
+
+// waitForAnalyticsIngestion waits for an analytics collection to ingest at least the number of items expected
+// to exist in the collection it is created on, as per the operations performed by Sirius.
+func waitForAnalyticsIngestion(task *AnalyticsTask, cluster *gocb.Cluster, collectionIdentifier string,
+	dataset string) error {
+	expectedCount, err := bulk_loading_cb.ExpectedItemCount(task.req, collectionIdentifier)
+	if err != nil {
+		return err
+	}
+
+	var ingestedCount int64
+	statement := fmt.Sprintf("SELECT VALUE COUNT(*) FROM %s;", dataset)
+	deadline := time.Now().Add(time.Duration(task.AnalyticsOperationConfig.IngestionTimeout) * time.Second)
+	for time.Now().Before(deadline) {
+		if task.req.ContextClosed() {
+			return nil
+		}
+		result, err := cluster.AnalyticsQuery(statement, &gocb.AnalyticsOptions{
+			Timeout: time.Duration(task.AnalyticsOperationConfig.Timeout) * time.Second,
+		})
+		if err == nil {
+			err = result.One(&ingestedCount)
+		}
+		if err == nil && ingestedCount >= expectedCount {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("%w : %s ingested %d of %d items", task_errors.ErrAnalyticsIngestionTimeout, dataset,
+		ingestedCount, expectedCount)
+}
+
+// runAnalyticsQueries runs analytics queries over a duration of time
+func runAnalyticsQueries(task *AnalyticsTask, cluster *gocb.Cluster) {
+
+	if task.req.ContextClosed() {
+		return
+	}
+
+	joinDataset := ""
+	if task.AnalyticsOperationConfig.JoinCollection != "" {
+		joinDataset = task.datasetName(task.AnalyticsOperationConfig.JoinScope,
+			task.AnalyticsOperationConfig.JoinCollection)
+	}
+	queries, err := task.Template.GenerateAnalyticsQueries(task.datasetName(task.Scope, task.Collection),
+		joinDataset)
+	if err != nil {
+		log.Println("Get sample analytics queries failed.....")
+		task.Result.ErrorOther = err.Error()
+		return
+	}
+
+	routineLimiter := make(chan struct{}, tasks.MaxConcurrentRoutines)
+	group := errgroup.Group{}
+
+	startTime := time.Now()
+	expirationTime := startTime.Add(time.Duration(task.AnalyticsOperationConfig.Duration) * time.Second)
+	for time.Now().Before(expirationTime) {
+		if task.req.ContextClosed() {
+			break
+		}
+		routineLimiter <- struct{}{}
+		group.Go(func() error {
+			for i := 0; i < len(queries); i++ {
+				start := time.Now()
+				rows, err := analyticsQuery(task, cluster, queries[i])
+				latency := time.Since(start)
+				if err != nil {
+					task.Result.IncrementQueryFailure(queries[i], err)
+				}
+				task.Result.RecordQuery(queries[i], latency, rows, err != nil)
+			}
+
+			<-routineLimiter
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+	close(routineLimiter)
+	task.Result.SummariseQueryResult(time.Since(startTime))
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+}
@@ -1,6 +1,7 @@
 package bulk_query_cb
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/couchbase/gocb/v2"
 	"github.com/couchbaselabs/sirius/internal/cb_sdk"
@@ -8,9 +9,11 @@ import (
 	"github.com/couchbaselabs/sirius/internal/task_errors"
 	"github.com/couchbaselabs/sirius/internal/task_result"
 	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/couchbaselabs/sirius/internal/tasks/bulk_loading_cb"
 	"github.com/couchbaselabs/sirius/internal/template"
 	"golang.org/x/sync/errgroup"
 	"log"
+	"math/rand"
 	"strings"
 	"time"
 )
@@ -33,7 +36,14 @@ type QueryTask struct {
 }
 
 func (task *QueryTask) Describe() string {
-	return " Query task runs N1QL query over a period of time over a bucket.\n"
+	return ` Query task runs N1QL query over a period of time over a bucket.
+If queries are supplied in operationConfig, they are run instead of the template queries. Each query is picked
+as per its weight and its parameters take values from the document of a random key loaded by Sirius.
+If validateResults is set, template queries with a computable result are run with request_plus consistency
+and their rows are compared with the rows expected as per the documents Sirius has loaded.
+Count, error rate, throughput, latency percentiles, rows returned and server reported metrics of every query
+are reported in the task result.
+`
 }
 
 func (task *QueryTask) CollectionIdentifier() string {
@@ -78,14 +88,15 @@ func (task *QueryTask) Config(req *tasks.Request, reRun bool) (int64, error) {
 		}
 
 		if err := cb_sdk.ConfigQueryOperationConfig(task.QueryOperationConfig); err != nil {
-			task.Result.ErrorOther = err.Error()
+			task.TaskPending = false
+			return 0, err
 		}
 
-		task.Template = template.InitialiseTemplate(task.QueryOperationConfig.Template)
-
 	} else {
 		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
 	}
+
+	task.Template = template.InitialiseTemplate(task.QueryOperationConfig.Template)
 	return task.ResultSeed, nil
 }
 
@@ -135,7 +146,13 @@ func (task *QueryTask) Do() error {
 		task.BuildIndex = false
 	}
 
-	runN1qlQuery(task, cluster, s, c.Collection)
+	if task.QueryOperationConfig.ValidateResults {
+		runQueryChecks(task, cluster, s, c.Collection)
+	} else if len(task.QueryOperationConfig.Queries) > 0 {
+		runUserQueries(task, cluster, c)
+	} else {
+		runN1qlQuery(task, cluster, s, c.Collection)
+	}
 
 	return task.TearUp()
 }
@@ -232,21 +249,260 @@ func runN1qlQuery(task *QueryTask, cluster *gocb.Cluster, scope *gocb.Scope, col
 		return
 	}
 
-	expirationTime := time.Now().Add(time.Duration(task.QueryOperationConfig.Duration) * time.Second)
+	startTime := time.Now()
+	expirationTime := startTime.Add(time.Duration(task.QueryOperationConfig.Duration) * time.Second)
 	for time.Now().Before(expirationTime) {
 		routineLimiter <- struct{}{}
 		group.Go(func() error {
 			for i := 0; i < len(queries); i++ {
-				_, err := cluster.Query(queries[i], &gocb.QueryOptions{})
+				runQuery(task, cluster, queries[i], &gocb.QueryOptions{})
+			}
+
+			<-routineLimiter
+			return nil
+		})
+
+	}
+
+	_ = group.Wait()
+	close(routineLimiter)
+	task.Result.SummariseQueryResult(time.Since(startTime))
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+}
+
+// runQuery runs a N1QL query reading all of its rows and records its latency, rows returned and the metrics
+// reported by the server against the query text.
+func runQuery(task *QueryTask, cluster *gocb.Cluster, query string, options *gocb.QueryOptions) {
+	options.Metrics = true
+	start := time.Now()
+	result, err := cluster.Query(query, options)
+	var rows uint64
+	if err == nil {
+		for result.Next() {
+			rows++
+		}
+		err = result.Err()
+	}
+	latency := time.Since(start)
+	if err != nil {
+		task.Result.IncrementQueryFailure(query, err)
+		task.Result.RecordQuery(query, latency, 0, true)
+		return
+	}
+	task.Result.RecordQuery(query, latency, rows, false)
+	if metaData, err := result.MetaData(); err == nil {
+		task.Result.RecordQueryMetrics(query, metaData.Metrics.ElapsedTime, metaData.Metrics.ExecutionTime,
+			metaData.Metrics.ResultCount)
+	}
+}
+
+// userQueryOptions returns the options for running a user supplied query with parameter values taken from the
+// given document. It also returns true if the query asks for at_plus scan consistency but runs not_bounded, as no
+// mutation token is recorded for the collection yet.
+func userQueryOptions(q *cb_sdk.QueryDefinition, document map[string]interface{},
+	collectionObject *cb_sdk.CollectionObject) (*gocb.QueryOptions, bool) {
+	options := &gocb.QueryOptions{
+		Adhoc:   !q.Prepared,
+		Timeout: time.Duration(q.Timeout) * time.Second,
+	}
+	atPlusFallback := false
+
+	switch q.ScanConsistency {
+	case cb_sdk.ScanConsistencyRequestPlus:
+		options.ScanConsistency = gocb.QueryScanConsistencyRequestPlus
+	case cb_sdk.ScanConsistencyAtPlus:
+		state := collectionObject.MutationState()
+		if state == nil {
+			atPlusFallback = true
+			options.ScanConsistency = gocb.QueryScanConsistencyNotBounded
+		} else {
+			options.ConsistentWith = state
+		}
+	default:
+		options.ScanConsistency = gocb.QueryScanConsistencyNotBounded
+	}
+
+	for _, p := range q.Parameters {
+		value := p.Value
+		if p.Path != "" {
+			value, _ = documentFieldValue(document, p.Path)
+		}
+		if p.Name != "" {
+			if options.NamedParameters == nil {
+				options.NamedParameters = make(map[string]interface{})
+			}
+			options.NamedParameters[p.Name] = value
+		} else {
+			options.PositionalParameters = append(options.PositionalParameters, value)
+		}
+	}
+	return options, atPlusFallback
+}
+
+// pickUserQuery returns a user supplied query picked randomly as per the weights of the queries.
+func pickUserQuery(queries []cb_sdk.QueryDefinition, r *rand.Rand) *cb_sdk.QueryDefinition {
+	totalWeight := 0
+	for i := range queries {
+		totalWeight += queries[i].Weight
+	}
+	n := r.Intn(totalWeight)
+	for i := range queries {
+		if n < queries[i].Weight {
+			return &queries[i]
+		}
+		n -= queries[i].Weight
+	}
+	return &queries[len(queries)-1]
+}
+
+// runUserQueries runs the user supplied queries over a duration of time
+func runUserQueries(task *QueryTask, cluster *gocb.Cluster, collectionObject *cb_sdk.CollectionObject) {
+
+	if task.req.ContextClosed() {
+		return
+	}
+
+	routineLimiter := make(chan struct{}, tasks.MaxConcurrentRoutines)
+	group := errgroup.Group{}
+	iteration := int64(0)
+
+	startTime := time.Now()
+	expirationTime := startTime.Add(time.Duration(task.QueryOperationConfig.Duration) * time.Second)
+	for time.Now().Before(expirationTime) {
+		if task.req.ContextClosed() {
+			break
+		}
+		routineLimiter <- struct{}{}
+		r := rand.New(rand.NewSource(task.ResultSeed + iteration))
+		iteration++
+		group.Go(func() error {
+			q := pickUserQuery(task.QueryOperationConfig.Queries, r)
+			document, err := sampleDocument(task.req, task.CollectionIdentifier(), task.Template, r)
+			if err != nil {
+				task.Result.IncrementQueryFailure(q.Statement, err)
+				<-routineLimiter
+				return nil
+			}
+
+			options, atPlusFallback := userQueryOptions(q, document, collectionObject)
+			if atPlusFallback {
+				task.Result.RecordAtPlusFallback(q.Statement)
+			}
+			runQuery(task, cluster, q.Statement, options)
+
+			<-routineLimiter
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+	close(routineLimiter)
+	task.Result.SummariseQueryResult(time.Since(startTime))
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+}
+
+// expectedQueryRows returns the JSON of the rows expected for every query check as per the documents expected
+// to exist in the collection. The documents are added to the aggregate of every check as they are built.
+func expectedQueryRows(task *QueryTask, checks []template.QueryCheck) (map[string]string, error) {
+	aggregates := make([]template.RowAggregate, len(checks))
+	for i, check := range checks {
+		aggregates[i] = check.NewAggregate()
+	}
+	err := bulk_loading_cb.ForEachExpectedDocument(task.req, task.CollectionIdentifier(),
+		func(document interface{}) error {
+			b, err := json.Marshal(document)
+			if err != nil {
+				return err
+			}
+			var m map[string]interface{}
+			if err := json.Unmarshal(b, &m); err != nil {
+				return err
+			}
+			for _, aggregate := range aggregates {
+				aggregate.Add(m)
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	expected := make(map[string]string)
+	for i, check := range checks {
+		b, err := json.Marshal(aggregates[i].Rows())
+		if err != nil {
+			return nil, err
+		}
+		expected[check.Query] = string(b)
+	}
+	return expected, nil
+}
+
+// queryRows runs a query with request_plus consistency and returns the JSON of the rows returned.
+func queryRows(cluster *gocb.Cluster, query string) (string, error) {
+	result, err := cluster.Query(query, &gocb.QueryOptions{
+		ScanConsistency: gocb.QueryScanConsistencyRequestPlus,
+	})
+	if err != nil {
+		return "", err
+	}
+	rows := []map[string]interface{}{}
+	for result.Next() {
+		var row map[string]interface{}
+		if err := result.Row(&row); err != nil {
+			return "", err
+		}
+		rows = append(rows, row)
+	}
+	if err := result.Err(); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(rows)
+	return string(b), err
+}
+
+// runQueryChecks runs the template queries with a computable result over a duration of time and compares
+// their rows with the expected rows.
+func runQueryChecks(task *QueryTask, cluster *gocb.Cluster, scope *gocb.Scope, collection *gocb.Collection) {
+
+	if task.req.ContextClosed() {
+		return
+	}
+
+	checks, err := task.Template.GenerateQueryChecks(task.Bucket, scope.Name(), collection.Name())
+	if err != nil {
+		log.Println("Get query checks failed.....")
+		task.Result.ErrorOther = err.Error()
+		return
+	}
+	expected, err := expectedQueryRows(task, checks)
+	if err != nil {
+		task.Result.ErrorOther = err.Error()
+		return
+	}
+
+	routineLimiter := make(chan struct{}, tasks.MaxConcurrentRoutines)
+	group := errgroup.Group{}
+
+	expirationTime := time.Now().Add(time.Duration(task.QueryOperationConfig.Duration) * time.Second)
+	for time.Now().Before(expirationTime) {
+		if task.req.ContextClosed() {
+			break
+		}
+		routineLimiter <- struct{}{}
+		group.Go(func() error {
+			for _, check := range checks {
+				actual, err := queryRows(cluster, check.Query)
 				if err != nil {
-					task.Result.IncrementQueryFailure(queries[i], err)
+					task.Result.IncrementQueryFailure(check.Query, err)
+					continue
 				}
+				task.Result.RecordQueryCheck(check.Query, expected[check.Query], actual)
 			}
 
 			<-routineLimiter
 			return nil
 		})
-
 	}
 
 	_ = group.Wait()
@@ -0,0 +1,48 @@
+package bulk_query_cb
+
+import (
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"math/rand"
+	"testing"
+)
+
+func TestUserQueryOptions(t *testing.T) {
+	document := map[string]interface{}{
+		"firstName": "Jane",
+		"address":   map[string]interface{}{"city": "Wardborough"},
+	}
+
+	positional := &cb_sdk.QueryDefinition{
+		Statement:  "SELECT * FROM c WHERE firstName = $1 AND age > $2",
+		Parameters: []cb_sdk.QueryParameter{{Path: "firstName"}, {Value: 30}},
+	}
+	options, atPlusFallback := userQueryOptions(positional, document, &cb_sdk.CollectionObject{})
+	if len(options.PositionalParameters) != 2 || options.PositionalParameters[0] != "Jane" ||
+		options.PositionalParameters[1] != 30 || !options.Adhoc || atPlusFallback {
+		t.Fatalf("unexpected positional options %v", options)
+	}
+
+	named := &cb_sdk.QueryDefinition{
+		Statement:       "SELECT * FROM c WHERE address.city = $city",
+		Parameters:      []cb_sdk.QueryParameter{{Name: "city", Path: "address.city"}},
+		ScanConsistency: cb_sdk.ScanConsistencyAtPlus,
+		Prepared:        true,
+	}
+	options, atPlusFallback = userQueryOptions(named, document, &cb_sdk.CollectionObject{})
+	if options.NamedParameters["city"] != "Wardborough" || options.Adhoc || options.ConsistentWith != nil ||
+		!atPlusFallback {
+		t.Fatalf("unexpected named options %v", options)
+	}
+}
+
+func TestPickUserQuery(t *testing.T) {
+	queries := []cb_sdk.QueryDefinition{{Statement: "a", Weight: 1}, {Statement: "b", Weight: 3}}
+	r := rand.New(rand.NewSource(1))
+	picked := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		picked[pickUserQuery(queries, r).Statement]++
+	}
+	if picked["a"] < 800 || picked["a"] > 1200 {
+		t.Fatalf("query picked against its weight : %v", picked)
+	}
+}
@@ -0,0 +1,406 @@
+package bulk_query_cb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbase/gocb/v2/search"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/couchbaselabs/sirius/internal/tasks/bulk_loading_cb"
+	"github.com/couchbaselabs/sirius/internal/template"
+	"golang.org/x/sync/errgroup"
+	"log"
+	"math"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var invalidSearchIndexCharacters = regexp.MustCompile(`[^0-9A-Za-z_\-]`)
+
+type SearchTask struct {
+	IdentifierToken       string                        `json:"identifierToken" doc:"true"`
+	ClusterConfig         *cb_sdk.ClusterConfig         `json:"clusterConfig" doc:"true"`
+	Bucket                string                        `json:"bucket" doc:"true"`
+	Scope                 string                        `json:"scope,omitempty" doc:"true"`
+	Collection            string                        `json:"collection,omitempty" doc:"true"`
+	SearchOperationConfig *cb_sdk.SearchOperationConfig `json:"operationConfig,omitempty" doc:"true"`
+	Template              template.Template             `json:"-" doc:"false"`
+	Operation             string                        `json:"operation" doc:"false"`
+	ResultSeed            int64                         `json:"resultSeed" doc:"false"`
+	TaskPending           bool                          `json:"taskPending" doc:"false"`
+	BuildIndex            bool                          `json:"buildIndex" doc:"false"`
+	Result                *task_result.TaskResult       `json:"-" doc:"false"`
+	req                   *tasks.Request                `json:"-" doc:"false"`
+}
+
+// searchQuery is a full text search query along with the shape it is reported against.
+type searchQuery struct {
+	shape string
+	query search.Query
+}
+
+func (task *SearchTask) Describe() string {
+	return `Search task creates a full text search index on the fields of the template, waits for the index to
+index the items loaded by Sirius in the collection and then runs a mix of match, term, numeric range,
+conjunction and geo distance queries over a period of time. Geo distance queries need documents with a geo point,
+loaded with the hotel_geo template. Queries are built from the documents of randomly picked keys. Latency and hit count of every query shape are reported in the task result.`
+}
+
+func (task *SearchTask) CollectionIdentifier() string {
+	clusterIdentifier, _ := cb_sdk.GetClusterIdentifier(task.ClusterConfig.ConnectionString)
+	return strings.Join([]string{task.IdentifierToken, clusterIdentifier, task.Bucket, task.Scope,
+		task.Collection}, ":")
+}
+
+func (task *SearchTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+func (task *SearchTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.BuildIndex = false
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+		task.Operation = tasks.SearchOperation
+		task.BuildIndex = true
+
+		if task.Bucket == "" {
+			task.Bucket = cb_sdk.DefaultBucket
+		}
+		if task.Scope == "" {
+			task.Scope = cb_sdk.DefaultScope
+		}
+		if task.Collection == "" {
+			task.Collection = cb_sdk.DefaultCollection
+		}
+
+		if err := cb_sdk.ConfigSearchOperationConfig(task.SearchOperationConfig); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+		if task.SearchOperationConfig.IndexName == "" {
+			task.SearchOperationConfig.IndexName = invalidSearchIndexCharacters.ReplaceAllString(
+				strings.Join([]string{"sirius", task.Bucket, task.Scope, task.Collection}, "_"), "_")
+		}
+
+	} else {
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+
+	task.Template = template.InitialiseTemplate(task.SearchOperationConfig.Template)
+	return task.ResultSeed, nil
+}
+
+func (task *SearchTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed)
+	}
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *SearchTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	cluster, err := task.req.GetCluster(task.ClusterConfig)
+	if err != nil {
+		task.Result.ErrorOther = err.Error()
+		return task.TearUp()
+	}
+
+	if _, err := task.req.GetCollection(task.ClusterConfig, task.Bucket, task.Scope, task.Collection); err != nil {
+		task.Result.ErrorOther = err.Error()
+		return task.TearUp()
+	}
+
+	fields, err := task.Template.GenerateSearchFields()
+	if err != nil || len(fields) == 0 {
+		task.Result.ErrorOther = task_errors.ErrNoSearchFields.Error()
+		return task.TearUp()
+	}
+
+	if task.BuildIndex {
+		if err := upsertSearchIndex(task, cluster, fields); err != nil {
+			task.Result.IncrementQueryFailure(fmt.Sprintf("Upsert search index %s on `%s`.%s.%s",
+				task.SearchOperationConfig.IndexName, task.Bucket, task.Scope, task.Collection), err)
+			return task.TearUp()
+		}
+		task.BuildIndex = false
+	}
+
+	if err := waitForSearchIndex(task, cluster); err != nil {
+		task.Result.ErrorOther = err.Error()
+		return task.TearUp()
+	}
+
+	runSearchQueries(task, cluster, fields)
+
+	return task.TearUp()
+}
+
+// searchIndexParams returns the params of a search index mapping the fields of the template for documents of
+// the collection.
+func searchIndexParams(scope string, collection string, fields []template.SearchField) map[string]interface{} {
+	newMapping := func() map[string]interface{} {
+		return map[string]interface{}{
+			"enabled":    true,
+			"dynamic":    false,
+			"properties": map[string]interface{}{},
+		}
+	}
+
+	typeMapping := newMapping()
+	for _, field := range fields {
+		node := typeMapping
+		parts := strings.Split(field.Path, ".")
+		for _, part := range parts[:len(parts)-1] {
+			properties := node["properties"].(map[string]interface{})
+			child, ok := properties[part].(map[string]interface{})
+			if !ok {
+				child = newMapping()
+				properties[part] = child
+			}
+			node = child
+		}
+		leaf := parts[len(parts)-1]
+		node["properties"].(map[string]interface{})[leaf] = map[string]interface{}{
+			"enabled": true,
+			"dynamic": false,
+			"fields": []interface{}{
+				map[string]interface{}{
+					"name":           leaf,
+					"type":           field.Type,
+					"index":          true,
+					"include_in_all": true,
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"doc_config": map[string]interface{}{
+			"mode":       "scope.collection.type_field",
+			"type_field": "type",
+		},
+		"mapping": map[string]interface{}{
+			"default_analyzer": "standard",
+			"default_mapping": map[string]interface{}{
+				"enabled": false,
+				"dynamic": false,
+			},
+			"index_dynamic": false,
+			"store_dynamic": false,
+			"type_field":    "_type",
+			"types": map[string]interface{}{
+				scope + "." + collection: typeMapping,
+			},
+		},
+		"store": map[string]interface{}{
+			"indexType": "scorch",
+		},
+	}
+}
+
+// upsertSearchIndex creates the search index or updates it if an index with the same name already exists.
+func upsertSearchIndex(task *SearchTask, cluster *gocb.Cluster, fields []template.SearchField) error {
+	manager := cluster.SearchIndexes()
+	index := gocb.SearchIndex{
+		Name:       task.SearchOperationConfig.IndexName,
+		SourceName: task.Bucket,
+		SourceType: "gocbcore",
+		Type:       "fulltext-index",
+		Params:     searchIndexParams(task.Scope, task.Collection, fields),
+	}
+
+	existingIndex, err := manager.GetIndex(index.Name, nil)
+	if err == nil {
+		index.UUID = existingIndex.UUID
+	} else if !errors.Is(err, gocb.ErrIndexNotFound) {
+		return err
+	}
+
+	return manager.UpsertIndex(index, nil)
+}
+
+// waitForSearchIndex waits for the search index to index at least the number of items expected to exist in the
+// collection as per the operations performed by Sirius.
+func waitForSearchIndex(task *SearchTask, cluster *gocb.Cluster) error {
+	expectedCount, err := bulk_loading_cb.ExpectedItemCount(task.req, task.CollectionIdentifier())
+	if err != nil {
+		return err
+	}
+
+	var indexedCount uint64
+	deadline := time.Now().Add(time.Duration(task.SearchOperationConfig.IndexTimeout) * time.Second)
+	for time.Now().Before(deadline) {
+		if task.req.ContextClosed() {
+			return nil
+		}
+		indexedCount, err = cluster.SearchIndexes().GetIndexedDocumentsCount(task.SearchOperationConfig.IndexName,
+			nil)
+		if err == nil && int64(indexedCount) >= expectedCount {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return fmt.Errorf("%w : indexed %d of %d items", task_errors.ErrSearchIndexTimeout, indexedCount,
+		expectedCount)
+}
+
+// buildSearchQueries returns match and term queries for text fields, numeric range queries for number fields,
+// boolean field queries, geo distance queries for geopoint fields and a conjunction of the first text and
+// number queries, all matching the given document.
+func buildSearchQueries(fields []template.SearchField, document map[string]interface{}) []searchQuery {
+	var queries []searchQuery
+	var textQuery, numberQuery search.Query
+
+	for _, field := range fields {
+		value, ok := documentFieldValue(document, field.Path)
+		if !ok {
+			continue
+		}
+		switch field.Type {
+		case template.SearchFieldText:
+			text, ok := value.(string)
+			if !ok || text == "" {
+				continue
+			}
+			matchQuery := search.NewMatchQuery(text).Field(field.Path)
+			queries = append(queries, searchQuery{shape: "match:" + field.Path, query: matchQuery})
+			if textQuery == nil {
+				textQuery = matchQuery
+			}
+			terms := strings.FieldsFunc(text, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+			})
+			if len(terms) > 0 {
+				queries = append(queries, searchQuery{shape: "term:" + field.Path,
+					query: search.NewTermQuery(strings.ToLower(terms[0])).Field(field.Path)})
+			}
+		case template.SearchFieldNumber:
+			number, ok := value.(float64)
+			if !ok {
+				continue
+			}
+			delta := math.Max(1, math.Abs(number)*0.1)
+			rangeQuery := search.NewNumericRangeQuery().Min(float32(number-delta), true).
+				Max(float32(number+delta), true).Field(field.Path)
+			queries = append(queries, searchQuery{shape: "range:" + field.Path, query: rangeQuery})
+			if numberQuery == nil {
+				numberQuery = rangeQuery
+			}
+		case template.SearchFieldBoolean:
+			b, ok := value.(bool)
+			if !ok {
+				continue
+			}
+			queries = append(queries, searchQuery{shape: "boolean:" + field.Path,
+				query: search.NewBooleanFieldQuery(b).Field(field.Path)})
+		case template.SearchFieldGeoPoint:
+			point, ok := value.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			lat, ok1 := point["lat"].(float64)
+			lon, ok2 := point["lon"].(float64)
+			if !ok1 || !ok2 {
+				continue
+			}
+			queries = append(queries, searchQuery{shape: "geo:" + field.Path,
+				query: search.NewGeoDistanceQuery(lon, lat, "100km").Field(field.Path)})
+		}
+	}
+
+	if textQuery != nil && numberQuery != nil {
+		queries = append(queries, searchQuery{shape: "conjunction",
+			query: search.NewConjunctionQuery(textQuery, numberQuery)})
+	}
+	return queries
+}
+
+// runSearchQueries runs search queries over a duration of time
+func runSearchQueries(task *SearchTask, cluster *gocb.Cluster, fields []template.SearchField) {
+
+	if task.req.ContextClosed() {
+		return
+	}
+
+	routineLimiter := make(chan struct{}, tasks.MaxConcurrentRoutines)
+	group := errgroup.Group{}
+	iteration := int64(0)
+
+	expirationTime := time.Now().Add(time.Duration(task.SearchOperationConfig.Duration) * time.Second)
+	for time.Now().Before(expirationTime) {
+		if task.req.ContextClosed() {
+			break
+		}
+		routineLimiter <- struct{}{}
+		r := rand.New(rand.NewSource(task.ResultSeed + iteration))
+		iteration++
+		group.Go(func() error {
+			document, err := sampleDocument(task.req, task.CollectionIdentifier(), task.Template, r)
+			if err != nil {
+				task.Result.IncrementQueryFailure("generate document for search queries", err)
+				<-routineLimiter
+				return nil
+			}
+
+			for _, q := range buildSearchQueries(fields, document) {
+				start := time.Now()
+				result, err := cluster.SearchQuery(task.SearchOperationConfig.IndexName, q.query,
+					&gocb.SearchOptions{
+						Limit:   task.SearchOperationConfig.Limit,
+						Timeout: time.Duration(task.SearchOperationConfig.Timeout) * time.Second,
+					})
+				if err == nil {
+					for result.Next() {
+					}
+					err = result.Err()
+				}
+				latency := time.Since(start)
+				if err != nil {
+					b, _ := json.Marshal(q.query)
+					task.Result.IncrementQueryFailure(fmt.Sprintf("%s %s", q.shape, b), err)
+					task.Result.RecordSearchQuery(q.shape, latency, 0, true)
+					continue
+				}
+				metaData, err := result.MetaData()
+				if err != nil {
+					task.Result.RecordSearchQuery(q.shape, latency, 0, false)
+					continue
+				}
+				task.Result.RecordSearchQuery(q.shape, latency, metaData.Metrics.TotalRows, false)
+			}
+
+			<-routineLimiter
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+	close(routineLimiter)
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+}
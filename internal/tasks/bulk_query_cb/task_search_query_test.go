@@ -0,0 +1,43 @@
+package bulk_query_cb
+
+import (
+	"github.com/couchbaselabs/sirius/internal/template"
+	"testing"
+)
+
+func TestBuildSearchQueries(t *testing.T) {
+	fields, _ := template.InitialiseTemplate("hotel").GenerateSearchFields()
+	document := map[string]interface{}{
+		"name":         "Jane Doe",
+		"price":        float64(2000),
+		"free_parking": true,
+		"geo":          map[string]interface{}{"lat": 12.5, "lon": 77.25},
+	}
+
+	shapes := make(map[string]struct{})
+	for _, q := range buildSearchQueries(fields, document) {
+		shapes[q.shape] = struct{}{}
+	}
+	for _, shape := range []string{"match:name", "term:name", "range:price", "boolean:free_parking", "geo:geo",
+		"conjunction"} {
+		if _, ok := shapes[shape]; !ok {
+			t.Fatalf("expected query shape %s, got %v", shape, shapes)
+		}
+	}
+	if len(shapes) != 6 {
+		t.Fatalf("queries built for fields missing in the document : %v", shapes)
+	}
+}
+
+func TestSearchIndexParams(t *testing.T) {
+	params := searchIndexParams("s", "c", []template.SearchField{{Path: "address.city", Type: template.SearchFieldText}})
+	types := params["mapping"].(map[string]interface{})["types"].(map[string]interface{})
+	mapping, ok := types["s.c"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("type mapping for s.c not found")
+	}
+	address := mapping["properties"].(map[string]interface{})["address"].(map[string]interface{})
+	if _, ok := address["properties"].(map[string]interface{})["city"]; !ok {
+		t.Fatalf("nested field address.city not mapped")
+	}
+}
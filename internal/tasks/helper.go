@@ -37,6 +37,37 @@ const (
 	SingleSubDocReadOperation    string = "singleSubDocRead"
 	SingleDocValidateOperation   string = "SingleDocValidate"
 	BucketWarmUpOperation        string = "BucketWarmUp"
+	IncrementOperation           string = "increment"
+	DecrementOperation           string = "decrement"
+	AppendOperation              string = "append"
+	PrependOperation             string = "prepend"
+	SingleIncrementOperation     string = "singleIncrement"
+	SingleDecrementOperation     string = "singleDecrement"
+	SingleAppendOperation        string = "singleAppend"
+	SinglePrependOperation       string = "singlePrepend"
+	LockOperation                string = "lock"
+	SingleLockOperation          string = "singleLock"
+	SingleUnlockOperation        string = "singleUnlock"
+	ScanOperation                string = "scan"
+	TransactionOperation         string = "transaction"
+	SearchOperation              string = "search"
+	AnalyticsOperation           string = "analytics"
+	CreateIndexOperation         string = "createIndex"
+	BuildIndexOperation          string = "buildIndex"
+	WatchIndexOperation          string = "watchIndex"
+	DropIndexOperation           string = "dropIndex"
+	ListIndexOperation           string = "listIndex"
+	CreateBucketOperation        string = "createBucket"
+	DropBucketOperation          string = "dropBucket"
+	FlushBucketOperation         string = "flushBucket"
+	CreateScopeOperation         string = "createScope"
+	DropScopeOperation           string = "dropScope"
+	CreateCollectionOperation    string = "createCollection"
+	DropCollectionOperation      string = "dropCollection"
+	FanOutOperation              string = "fanOut"
+	PlanOperation                string = "plan"
+	ScheduleOperation            string = "schedule"
+	VerifyDurabilityOperation    string = "verifyDurability"
 )
 
 func buildKeyAndValues(doc map[string]any, result map[string]any, startString string) {
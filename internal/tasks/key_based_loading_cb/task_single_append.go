@@ -0,0 +1,178 @@
+package key_based_loading_cb
+
+import (
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/docgenerator"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/jaswdr/faker"
+	"golang.org/x/sync/errgroup"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+type SingleAppendTask struct {
+	IdentifierToken       string                  `json:"identifierToken" doc:"true"`
+	ClusterConfig         *cb_sdk.ClusterConfig   `json:"clusterConfig" doc:"true"`
+	Bucket                string                  `json:"bucket" doc:"true"`
+	Scope                 string                  `json:"scope,omitempty" doc:"true"`
+	Collection            string                  `json:"collection,omitempty" doc:"true"`
+	BinaryOptions         *cb_sdk.BinaryOptions   `json:"binaryOptions,omitempty" doc:"true"`
+	SingleOperationConfig *SingleOperationConfig  `json:"singleOperationConfig" doc:"true"`
+	Operation             string                  `json:"operation" doc:"false"`
+	ResultSeed            int64                   `json:"resultSeed" doc:"false"`
+	TaskPending           bool                    `json:"taskPending" doc:"false"`
+	Result                *task_result.TaskResult `json:"-" doc:"false"`
+	req                   *tasks.Request          `json:"-" doc:"false"`
+}
+
+func (task *SingleAppendTask) Describe() string {
+	return "Single append task appends raw bytes of docSize to the end of keys in Couchbase.\n"
+}
+
+func (task *SingleAppendTask) CollectionIdentifier() string {
+	clusterIdentifier, _ := cb_sdk.GetClusterIdentifier(task.ClusterConfig.ConnectionString)
+	return strings.Join([]string{task.IdentifierToken, clusterIdentifier, task.Bucket, task.Scope,
+		task.Collection}, ":")
+}
+
+func (task *SingleAppendTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+// Config configures  the append task
+func (task *SingleAppendTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+		task.Operation = tasks.SingleAppendOperation
+
+		if task.Bucket == "" {
+			task.Bucket = cb_sdk.DefaultBucket
+		}
+		if task.Scope == "" {
+			task.Scope = cb_sdk.DefaultScope
+		}
+		if task.Collection == "" {
+			task.Collection = cb_sdk.DefaultCollection
+		}
+
+		if err := cb_sdk.ConfigBinaryOptions(task.BinaryOptions); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+		if err := ConfigSingleOperationConfig(task.SingleOperationConfig); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+		if task.SingleOperationConfig.DocSize <= 0 {
+			task.SingleOperationConfig.DocSize = docgenerator.DefaultDocSize
+		}
+	} else {
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+	return task.ResultSeed, nil
+}
+
+func (task *SingleAppendTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed)
+	}
+	task.Result = nil
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *SingleAppendTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	collectionObject, err1 := task.req.GetCollection(task.ClusterConfig, task.Bucket, task.Scope,
+		task.Collection)
+
+	if err1 != nil {
+		task.Result.ErrorOther = err1.Error()
+		task.Result.FailWholeSingleOperation(task.SingleOperationConfig.Keys, err1)
+		return task.TearUp()
+	}
+
+	singleAppendDocuments(task, collectionObject)
+
+	task.Result.Success = int64(len(task.SingleOperationConfig.Keys)) - task.Result.Failure
+	return task.TearUp()
+}
+
+// singleAppendDocuments appends generated values to keys in a bucket.scope.collection.
+func singleAppendDocuments(task *SingleAppendTask, collectionObject *cb_sdk.CollectionObject) {
+
+	if task.req.ContextClosed() {
+		return
+	}
+
+	routineLimiter := make(chan struct{}, tasks.MaxConcurrentRoutines)
+	dataChannel := make(chan string, tasks.MaxConcurrentRoutines)
+	fake := faker.NewWithSeed(rand.NewSource(task.ResultSeed))
+	value := []byte(fake.RandomStringWithLength(task.SingleOperationConfig.DocSize))
+
+	group := errgroup.Group{}
+
+	for _, data := range task.SingleOperationConfig.Keys {
+
+		if task.req.ContextClosed() {
+			close(routineLimiter)
+			close(dataChannel)
+			return
+		}
+
+		routineLimiter <- struct{}{}
+		dataChannel <- data
+
+		group.Go(func() error {
+			key := <-dataChannel
+
+			initTime := time.Now().UTC().Format(time.RFC850)
+			result, err := collectionObject.Collection.Binary().Append(key, value, &gocb.AppendOptions{
+				Cas:             gocb.Cas(task.BinaryOptions.Cas),
+				DurabilityLevel: cb_sdk.GetDurability(task.BinaryOptions.Durability),
+				PersistTo:       task.BinaryOptions.PersistTo,
+				ReplicateTo:     task.BinaryOptions.ReplicateTo,
+				Timeout:         time.Duration(task.BinaryOptions.Timeout) * time.Second,
+			})
+
+			if err != nil {
+				task.Result.CreateSingleErrorResult(initTime, key, err.Error(), false, 0)
+				<-routineLimiter
+				return err
+			}
+
+			collectionObject.RecordMutation(result)
+			task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(result.Cas()))
+			<-routineLimiter
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+	close(routineLimiter)
+	close(dataChannel)
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+}
@@ -173,6 +173,7 @@ func singleInsertDocuments(task *SingleInsertTask, collectionObject *cb_sdk.Coll
 
 			}
 
+			collectionObject.RecordMutation(m)
 			task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(m.Cas()))
 			<-routineLimiter
 			return nil
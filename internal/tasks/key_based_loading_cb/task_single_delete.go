@@ -159,6 +159,7 @@ func singleDeleteDocuments(task *SingleDeleteTask, collectionObject *cb_sdk.Coll
 				return err
 			}
 
+			collectionObject.RecordMutation(r)
 			task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(r.Cas()))
 			<-routineLimiter
 			return nil
@@ -0,0 +1,175 @@
+package key_based_loading_cb
+
+import (
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"golang.org/x/sync/errgroup"
+	"log"
+	"strings"
+	"time"
+)
+
+type SingleLockTask struct {
+	IdentifierToken       string                  `json:"identifierToken" doc:"true"`
+	ClusterConfig         *cb_sdk.ClusterConfig   `json:"clusterConfig" doc:"true"`
+	Bucket                string                  `json:"bucket" doc:"true"`
+	Scope                 string                  `json:"scope,omitempty" doc:"true"`
+	Collection            string                  `json:"collection,omitempty" doc:"true"`
+	LockOptions           *cb_sdk.LockOptions     `json:"lockOptions,omitempty" doc:"true"`
+	SingleOperationConfig *SingleOperationConfig  `json:"singleOperationConfig" doc:"true"`
+	Operation             string                  `json:"operation" doc:"false"`
+	ResultSeed            int64                   `json:"resultSeed" doc:"false"`
+	TaskPending           bool                    `json:"taskPending" doc:"false"`
+	Result                *task_result.TaskResult `json:"-" doc:"false"`
+	req                   *tasks.Request          `json:"-" doc:"false"`
+}
+
+func (task *SingleLockTask) Describe() string {
+	return `Single lock task locks keys in Couchbase using get and lock for lockTime seconds.
+While a key is locked, contentionAttempts mutations are attempted on it. The key is held for holdTime
+milliseconds and then unlocked with the CAS received while locking, unless skipUnlock is set.
+The CAS of the lock is recorded so that the key can later be unlocked by the single unlock task.`
+}
+
+func (task *SingleLockTask) CollectionIdentifier() string {
+	clusterIdentifier, _ := cb_sdk.GetClusterIdentifier(task.ClusterConfig.ConnectionString)
+	return strings.Join([]string{task.IdentifierToken, clusterIdentifier, task.Bucket, task.Scope,
+		task.Collection}, ":")
+}
+
+func (task *SingleLockTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+// Config configures  the lock task
+func (task *SingleLockTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+		task.Operation = tasks.SingleLockOperation
+
+		if task.Bucket == "" {
+			task.Bucket = cb_sdk.DefaultBucket
+		}
+		if task.Scope == "" {
+			task.Scope = cb_sdk.DefaultScope
+		}
+		if task.Collection == "" {
+			task.Collection = cb_sdk.DefaultCollection
+		}
+
+		if err := cb_sdk.ConfigLockOptions(task.LockOptions); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+		if err := ConfigSingleOperationConfig(task.SingleOperationConfig); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+	} else {
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+	return task.ResultSeed, nil
+}
+
+func (task *SingleLockTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed)
+	}
+	task.Result = nil
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *SingleLockTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	collectionObject, err1 := task.req.GetCollection(task.ClusterConfig, task.Bucket, task.Scope,
+		task.Collection)
+
+	if err1 != nil {
+		task.Result.ErrorOther = err1.Error()
+		task.Result.FailWholeSingleOperation(task.SingleOperationConfig.Keys, err1)
+		return task.TearUp()
+	}
+
+	singleLockDocuments(task, collectionObject)
+
+	task.Result.Success = int64(len(task.SingleOperationConfig.Keys)) - task.Result.Failure
+	return task.TearUp()
+}
+
+// singleLockDocuments locks, contends on and unlocks keys in a bucket.scope.collection.
+func singleLockDocuments(task *SingleLockTask, collectionObject *cb_sdk.CollectionObject) {
+
+	if task.req.ContextClosed() {
+		return
+	}
+
+	routineLimiter := make(chan struct{}, tasks.MaxConcurrentRoutines)
+	dataChannel := make(chan string, tasks.MaxConcurrentRoutines)
+
+	group := errgroup.Group{}
+
+	for _, data := range task.SingleOperationConfig.Keys {
+
+		if task.req.ContextClosed() {
+			close(routineLimiter)
+			close(dataChannel)
+			return
+		}
+
+		routineLimiter <- struct{}{}
+		dataChannel <- data
+
+		group.Go(func() error {
+			key := <-dataChannel
+
+			initTime := time.Now().UTC().Format(time.RFC850)
+			result, err := cb_sdk.LockDocument(collectionObject.Collection, key, task.LockOptions)
+			if err == nil {
+				if !task.LockOptions.SkipUnlock {
+					err = cb_sdk.UnlockDocument(collectionObject.Collection, key, task.LockOptions, &result)
+				}
+				task.Result.RecordLockTiming(result.AcquireTime, result.HoldTime, result.UnlockTime,
+					result.Unlocked, result.ContentionAttempts, result.DocumentLockedErrors)
+				if err == nil {
+					err = result.ContentionError
+				}
+			}
+
+			if err != nil {
+				task.Result.CreateSingleErrorResult(initTime, key, err.Error(), false, uint64(result.Cas))
+				<-routineLimiter
+				return err
+			}
+
+			task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(result.Cas))
+			<-routineLimiter
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+	close(routineLimiter)
+	close(dataChannel)
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+}
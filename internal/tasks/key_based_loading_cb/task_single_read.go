@@ -1,6 +1,8 @@
 package key_based_loading_cb
 
 import (
+	"errors"
+	"github.com/couchbase/gocb/v2"
 	"github.com/couchbaselabs/sirius/internal/cb_sdk"
 	"github.com/couchbaselabs/sirius/internal/task_errors"
 	"github.com/couchbaselabs/sirius/internal/task_result"
@@ -17,6 +19,7 @@ type SingleReadTask struct {
 	Bucket                string                  `json:"bucket" doc:"true"`
 	Scope                 string                  `json:"scope,omitempty" doc:"true"`
 	Collection            string                  `json:"collection,omitempty" doc:"true"`
+	ReadOptions           *cb_sdk.ReadOptions     `json:"readOptions,omitempty" doc:"true"`
 	SingleOperationConfig *SingleOperationConfig  `json:"singleOperationConfig" doc:"true"`
 	Operation             string                  `json:"operation" doc:"false"`
 	ResultSeed            int64                   `json:"resultSeed" doc:"false"`
@@ -26,7 +29,12 @@ type SingleReadTask struct {
 }
 
 func (task *SingleReadTask) Describe() string {
-	return "Single read task reads key value in couchbase and validates.\n"
+	return `Single read task reads key value in couchbase and validates.
+The read mode is selected by readOptions.readMode as get (default, supports projections), getAndTouch
+(updates expiry), exists, getAnyReplica or getAllReplicas.
+Keys which do not exist are failures in every read mode. The expiry set by getAndTouch is not tracked, so
+validating the documents afterwards does not account for their expiry.
+`
 }
 
 func (task *SingleReadTask) CollectionIdentifier() string {
@@ -71,11 +79,24 @@ func (task *SingleReadTask) Config(req *tasks.Request, reRun bool) (int64, error
 			task.Collection = cb_sdk.DefaultCollection
 		}
 
+		if task.ReadOptions == nil {
+			task.ReadOptions = &cb_sdk.ReadOptions{}
+		}
+		if err := cb_sdk.ConfigReadOptions(task.ReadOptions); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
 		if err := ConfigSingleOperationConfig(task.SingleOperationConfig); err != nil {
 			task.TaskPending = false
 			return 0, err
 		}
 	} else {
+		// read tasks stored before read modes were introduced don't have ReadOptions.
+		if task.ReadOptions == nil {
+			task.ReadOptions = &cb_sdk.ReadOptions{}
+			_ = cb_sdk.ConfigReadOptions(task.ReadOptions)
+		}
 		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
 	}
 	return task.ResultSeed, nil
@@ -139,14 +160,18 @@ func singleReadDocuments(task *SingleReadTask, collectionObject *cb_sdk.Collecti
 				task.SingleOperationConfig.DocSize, false)
 
 			initTime := time.Now().UTC().Format(time.RFC850)
-			result, err := collectionObject.Collection.Get(key, nil)
+			result, err := cb_sdk.ReadDocument(collectionObject.Collection, key, task.ReadOptions)
 			if err != nil {
+				if result.ReadMode == cb_sdk.ReadModeExists && errors.Is(err, gocb.ErrDocumentNotFound) {
+					task.Result.RecordRead(result)
+				}
 				task.Result.CreateSingleErrorResult(initTime, key, err.Error(), false, 0)
 				<-routineLimiter
 				return err
 			}
 
-			task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(result.Cas()))
+			task.Result.RecordRead(result)
+			task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(result.Cas))
 			<-routineLimiter
 			return nil
 		})
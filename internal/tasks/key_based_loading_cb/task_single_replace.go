@@ -172,6 +172,7 @@ func singleReplaceDocuments(task *SingleReplaceTask, collectionObject *cb_sdk.Co
 				return err
 			}
 
+			collectionObject.RecordMutation(result)
 			task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(result.Cas()))
 			<-routineLimiter
 			return nil
@@ -171,6 +171,7 @@ func singleDeleteSubDocuments(task *SingleSubDocDelete, collectionObject *cb_sdk
 		if !task.RemoveSpecOptions.IsXattr {
 			documentMetaData.IncrementMutationCount()
 		}
+		collectionObject.RecordMutation(&result.MutationResult)
 		task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(result.Cas()))
 	}
 
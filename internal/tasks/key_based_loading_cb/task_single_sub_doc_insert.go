@@ -176,6 +176,7 @@ func singleInsertSubDocuments(task *SingleSubDocInsert, collectionObject *cb_sdk
 		if !task.InsertSpecOptions.IsXattr {
 			documentMetaData.IncrementMutationCount()
 		}
+		collectionObject.RecordMutation(&result.MutationResult)
 		task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(result.Cas()))
 	}
 
@@ -173,6 +173,7 @@ func singleReplaceSubDocuments(task *SingleSubDocReplace, collectionObject *cb_s
 		if !task.ReplaceSpecOptions.IsXattr {
 			documentMetaData.IncrementMutationCount()
 		}
+		collectionObject.RecordMutation(&result.MutationResult)
 		task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(result.Cas()))
 	}
 
@@ -186,6 +186,7 @@ func singleUpsertSubDocuments(task *SingleSubDocUpsert, collectionObject *cb_sdk
 		if !task.InsertSpecOptions.IsXattr {
 			documentMetaData.IncrementMutationCount()
 		}
+		collectionObject.RecordMutation(&result.MutationResult)
 		task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(result.Cas()))
 	}
 
@@ -159,6 +159,7 @@ func singleTouchDocuments(task *SingleTouchTask, collectionObject *cb_sdk.Collec
 				return err
 			}
 
+			collectionObject.RecordMutation(result)
 			task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(result.Cas()))
 			<-routineLimiter
 			return nil
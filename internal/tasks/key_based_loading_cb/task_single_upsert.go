@@ -175,6 +175,7 @@ func singleUpsertDocuments(task *SingleUpsertTask, collectionObject *cb_sdk.Coll
 				return err
 			}
 
+			collectionObject.RecordMutation(m)
 			task.Result.CreateSingleErrorResult(initTime, key, "", true, uint64(m.Cas()))
 			<-routineLimiter
 			return nil
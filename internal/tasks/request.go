@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 )
 
 const RequestPath = "./internal/tasks/request_logs"
@@ -28,6 +29,11 @@ type Request struct {
 	lock              sync.Mutex                   `json:"-" doc:"false"`
 	ctx               context.Context              `json:"-"`
 	cancel            context.CancelFunc           `json:"-"`
+	tasksVersion      atomic.Int64                 `json:"-"`
+	allTasks          []TaskWithIdentifier         `json:"-"`
+	allTasksVersion   int64                        `json:"-"`
+	allTasksCached    bool                         `json:"-"`
+	allTasksLock      sync.Mutex                   `json:"-"`
 }
 
 // NewRequest return  an instance of Request
@@ -97,6 +103,7 @@ func (r *Request) ClearAllTask() {
 	for i := range r.Tasks {
 		r.Tasks[i].Task = nil
 	}
+	r.InvalidateTasks()
 }
 
 // AddTask will add tasks.Task with operation type.
@@ -107,10 +114,43 @@ func (r *Request) AddTask(o string, t Task) error {
 		Operation: o,
 		Task:      t,
 	})
+	r.InvalidateTasks()
 	err := r.saveRequestIntoFile()
 	return err
 }
 
+// AllTasks returns the tasks of the request in order, with the subtasks of every TaskGroup following it. The list is
+// built again only after InvalidateTasks, as it is read for every document while validating, and must not be
+// modified.
+func (r *Request) AllTasks() []TaskWithIdentifier {
+	version := r.tasksVersion.Load()
+	r.allTasksLock.Lock()
+	defer r.allTasksLock.Unlock()
+	if !r.allTasksCached || r.allTasksVersion != version {
+		r.allTasks = flattenTasks(r.Tasks)
+		r.allTasksVersion = version
+		r.allTasksCached = true
+	}
+	return r.allTasks
+}
+
+// InvalidateTasks makes AllTasks build the list of tasks again. It is called when a task is added and by a TaskGroup
+// when its subtasks change.
+func (r *Request) InvalidateTasks() {
+	r.tasksVersion.Add(1)
+}
+
+func flattenTasks(taskList []TaskWithIdentifier) []TaskWithIdentifier {
+	var result []TaskWithIdentifier
+	for _, t := range taskList {
+		result = append(result, t)
+		if group, ok := t.Task.(TaskGroup); ok {
+			result = append(result, flattenTasks(group.Subtasks())...)
+		}
+	}
+	return result
+}
+
 // AddToSeedEnd will update the Request.SeedEnd by  adding count into it.
 func (r *Request) AddToSeedEnd(collectionMetaData *meta_data.CollectionMetaData, count int64) {
 	collectionMetaData.SeedEnd += count
@@ -182,6 +222,30 @@ func (r *Request) GetScope(config *cb_sdk.ClusterConfig, bucket string, scope st
 	return r.connectionManager.GetScope(config, bucket, scope)
 }
 
+func (r *Request) RemoveBucket(config *cb_sdk.ClusterConfig, bucket string) {
+	r.connectionManager.RemoveBucket(config, bucket)
+}
+
+func (r *Request) RemoveScope(config *cb_sdk.ClusterConfig, bucket, scope string) {
+	r.connectionManager.RemoveScope(config, bucket, scope)
+}
+
+func (r *Request) RemoveCollection(config *cb_sdk.ClusterConfig, bucket, scope, collection string) {
+	r.connectionManager.RemoveCollection(config, bucket, scope, collection)
+}
+
+// CheckConnections runs a health check of the cluster connections of the request and returns their state.
+func (r *Request) CheckConnections() []cb_sdk.ClusterState {
+	r.ReconnectionManager()
+	return r.connectionManager.CheckHealth()
+}
+
+// ConnectionStates returns the state of the cluster connections of the request as per the last health check.
+func (r *Request) ConnectionStates() []cb_sdk.ClusterState {
+	r.ReconnectionManager()
+	return r.connectionManager.ConnectionStates()
+}
+
 // ReadRequestFromFile will return Request from the disk.
 func ReadRequestFromFile(identifier string) (*Request, error) {
 	cwd, err := os.Getwd()
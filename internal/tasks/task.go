@@ -7,3 +7,10 @@ type Task interface {
 	CheckIfPending() bool
 	TearUp() error
 }
+
+// TaskGroup is a Task which runs other tasks as a part of it, e.g. a bulk operation fanned out across collections.
+// A TaskGroup calls Request.InvalidateTasks when its subtasks change after it was added to the request.
+type TaskGroup interface {
+	Task
+	Subtasks() []TaskWithIdentifier
+}
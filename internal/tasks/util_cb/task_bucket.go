@@ -0,0 +1,135 @@
+package util_cb
+
+import (
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"log"
+	"time"
+)
+
+type BucketTask struct {
+	IdentifierToken string                  `json:"identifierToken" doc:"true"`
+	ClusterConfig   *cb_sdk.ClusterConfig   `json:"clusterConfig" doc:"true"`
+	Bucket          string                  `json:"bucket" doc:"true"`
+	BucketOptions   *cb_sdk.BucketOptions   `json:"bucketOptions,omitempty" doc:"true"`
+	Operation       string                  `json:"operation" doc:"false"`
+	ResultSeed      int64                   `json:"resultSeed" doc:"false"`
+	TaskPending     bool                    `json:"taskPending" doc:"false"`
+	Result          *task_result.TaskResult `json:"-" doc:"false"`
+	req             *tasks.Request          `json:"-" doc:"false"`
+}
+
+func (task *BucketTask) Describe() string {
+	return `Bucket task manages a bucket of the cluster. /create-bucket creates the bucket with the ram quota,
+replicas, storage backend, eviction policy, compression mode and maxTTL of bucketOptions and waits for it to be
+ready. /drop-bucket drops the bucket and /flush-bucket flushes it, which requires flush to be enabled on it.`
+}
+
+func (task *BucketTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+// Config configures the task for the bucket operation set in task.Operation before calling it.
+func (task *BucketTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	switch task.Operation {
+	case tasks.CreateBucketOperation, tasks.DropBucketOperation, tasks.FlushBucketOperation:
+	default:
+		task.TaskPending = false
+		return 0, task_errors.ErrInvalidBucketOperation
+	}
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+
+		if task.Bucket == "" {
+			task.Bucket = cb_sdk.DefaultBucket
+		}
+
+		if task.BucketOptions == nil {
+			task.BucketOptions = &cb_sdk.BucketOptions{}
+		}
+		if err := cb_sdk.ConfigBucketOptions(task.BucketOptions); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+	} else {
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+	return task.ResultSeed, nil
+}
+
+func (task *BucketTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed, task.Operation)
+	}
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *BucketTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	cluster, err := task.req.GetCluster(task.ClusterConfig)
+	if err != nil {
+		task.Result.ErrorOther = err.Error()
+		return task.TearUp()
+	}
+
+	timeout := time.Duration(task.BucketOptions.Timeout) * time.Second
+	switch task.Operation {
+	case tasks.CreateBucketOperation:
+		err = cluster.Buckets().CreateBucket(bucketSettings(task.Bucket, task.BucketOptions),
+			&gocb.CreateBucketOptions{Timeout: timeout})
+		if err == nil {
+			_, err = task.req.GetBucket(task.ClusterConfig, task.Bucket)
+		}
+	case tasks.DropBucketOperation:
+		err = cluster.Buckets().DropBucket(task.Bucket, &gocb.DropBucketOptions{Timeout: timeout})
+		task.req.RemoveBucket(task.ClusterConfig, task.Bucket)
+	case tasks.FlushBucketOperation:
+		err = cluster.Buckets().FlushBucket(task.Bucket, &gocb.FlushBucketOptions{Timeout: timeout})
+	}
+	task.Result.RecordManagementOutcome(task.Bucket, err)
+
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	return task.TearUp()
+}
+
+// bucketSettings returns the settings for creating a bucket as per BucketOptions.
+func bucketSettings(bucket string, b *cb_sdk.BucketOptions) gocb.CreateBucketSettings {
+	return gocb.CreateBucketSettings{
+		BucketSettings: gocb.BucketSettings{
+			Name:                 bucket,
+			FlushEnabled:         b.FlushEnabled,
+			ReplicaIndexDisabled: b.ReplicaIndexDisabled,
+			RAMQuotaMB:           b.RamQuotaMB,
+			NumReplicas:          b.NumReplicas,
+			BucketType:           gocb.BucketType(b.BucketType),
+			EvictionPolicy:       gocb.EvictionPolicyType(b.EvictionPolicy),
+			MaxExpiry:            time.Duration(b.MaxTTL) * time.Second,
+			CompressionMode:      gocb.CompressionMode(b.CompressionMode),
+			StorageBackend:       gocb.StorageBackend(b.StorageBackend),
+		},
+		ConflictResolutionType: gocb.ConflictResolutionType(b.ConflictResolutionType),
+	}
+}
@@ -0,0 +1,195 @@
+package util_cb
+
+import (
+	"errors"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"golang.org/x/sync/errgroup"
+	"log"
+	"strings"
+	"time"
+)
+
+type CollectionTask struct {
+	IdentifierToken   string                    `json:"identifierToken" doc:"true"`
+	ClusterConfig     *cb_sdk.ClusterConfig     `json:"clusterConfig" doc:"true"`
+	Bucket            string                    `json:"bucket" doc:"true"`
+	CollectionOptions *cb_sdk.CollectionOptions `json:"collectionOptions,omitempty" doc:"true"`
+	Operation         string                    `json:"operation" doc:"false"`
+	ResultSeed        int64                     `json:"resultSeed" doc:"false"`
+	TaskPending       bool                      `json:"taskPending" doc:"false"`
+	Result            *task_result.TaskResult   `json:"-" doc:"false"`
+	req               *tasks.Request            `json:"-" doc:"false"`
+}
+
+func (task *CollectionTask) Describe() string {
+	return `Collection task creates and drops scopes and collections of a bucket in bulk. Scopes and collections
+are named by patterns where every [start-end] is expanded to the numbers in the range, e.g. scopes as scope_[0-9]
+and collections as coll_[0-99] name 1000 collections. /create-scope and /drop-scope create and drop the scopes.
+/create-collection creates the collections in every scope, creating the scopes which do not exist, and
+/drop-collection drops them. Failures are reported against the scope or scope.collection name.`
+}
+
+func (task *CollectionTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+// Config configures the task for the scope or collection operation set in task.Operation before calling it.
+func (task *CollectionTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	switch task.Operation {
+	case tasks.CreateScopeOperation, tasks.DropScopeOperation, tasks.CreateCollectionOperation,
+		tasks.DropCollectionOperation:
+	default:
+		task.TaskPending = false
+		return 0, task_errors.ErrInvalidCollectionOperation
+	}
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+
+		if task.Bucket == "" {
+			task.Bucket = cb_sdk.DefaultBucket
+		}
+
+		if err := cb_sdk.ConfigCollectionOptions(task.CollectionOptions); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+		if task.CollectionOptions.Collections == "" && (task.Operation == tasks.CreateCollectionOperation ||
+			task.Operation == tasks.DropCollectionOperation) {
+			task.TaskPending = false
+			return 0, task_errors.ErrCollectionsMissing
+		}
+
+	} else {
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+	return task.ResultSeed, nil
+}
+
+func (task *CollectionTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed, task.Operation)
+	}
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *CollectionTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	bucket, err := task.req.GetBucket(task.ClusterConfig, task.Bucket)
+	if err != nil {
+		task.Result.ErrorOther = err.Error()
+		return task.TearUp()
+	}
+
+	scopes, _ := cb_sdk.ExpandNamePattern(task.CollectionOptions.Scopes)
+	collections, _ := cb_sdk.ExpandNamePattern(task.CollectionOptions.Collections)
+
+	switch task.Operation {
+	case tasks.CreateScopeOperation:
+		runForEach(task, scopes, func(scope string) error {
+			return createScope(task, bucket, scope)
+		})
+	case tasks.DropScopeOperation:
+		runForEach(task, scopes, func(scope string) error {
+			err := bucket.Collections().DropScope(scope, &gocb.DropScopeOptions{Timeout: task.timeout()})
+			task.req.RemoveScope(task.ClusterConfig, task.Bucket, scope)
+			return err
+		})
+	case tasks.CreateCollectionOperation:
+		for _, scope := range scopes {
+			if err := createScope(task, bucket, scope); err != nil && !errors.Is(err, gocb.ErrScopeExists) {
+				task.Result.RecordManagementOutcome(scope, err)
+			}
+		}
+		runForEach(task, collectionSpecs(scopes, collections), func(name string) error {
+			return bucket.Collections().CreateCollection(task.collectionSpec(name),
+				&gocb.CreateCollectionOptions{Timeout: task.timeout()})
+		})
+	case tasks.DropCollectionOperation:
+		runForEach(task, collectionSpecs(scopes, collections), func(name string) error {
+			spec := task.collectionSpec(name)
+			err := bucket.Collections().DropCollection(spec, &gocb.DropCollectionOptions{Timeout: task.timeout()})
+			task.req.RemoveCollection(task.ClusterConfig, task.Bucket, spec.ScopeName, spec.Name)
+			return err
+		})
+	}
+
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	return task.TearUp()
+}
+
+func (task *CollectionTask) timeout() time.Duration {
+	return time.Duration(task.CollectionOptions.Timeout) * time.Second
+}
+
+// collectionSpec returns the gocb.CollectionSpec of a collection named as scope.collection.
+func (task *CollectionTask) collectionSpec(name string) gocb.CollectionSpec {
+	scope, collection, _ := strings.Cut(name, ".")
+	return gocb.CollectionSpec{
+		Name:      collection,
+		ScopeName: scope,
+		MaxExpiry: time.Duration(task.CollectionOptions.MaxTTL) * time.Second,
+	}
+}
+
+// collectionSpecs returns the names of the collections in every scope as scope.collection.
+func collectionSpecs(scopes []string, collections []string) []string {
+	var names []string
+	for _, scope := range scopes {
+		for _, collection := range collections {
+			names = append(names, scope+"."+collection)
+		}
+	}
+	return names
+}
+
+func createScope(task *CollectionTask, bucket *gocb.Bucket, scope string) error {
+	if scope == cb_sdk.DefaultScope {
+		return gocb.ErrScopeExists
+	}
+	return bucket.Collections().CreateScope(scope, &gocb.CreateScopeOptions{Timeout: task.timeout()})
+}
+
+// runForEach runs a management operation on every name concurrently and records its outcome.
+func runForEach(task *CollectionTask, names []string, operation func(name string) error) {
+	routineLimiter := make(chan struct{}, task.CollectionOptions.Concurrency)
+	group := errgroup.Group{}
+
+	for _, name := range names {
+		if task.req.ContextClosed() {
+			break
+		}
+		name := name
+		routineLimiter <- struct{}{}
+		group.Go(func() error {
+			task.Result.RecordManagementOutcome(name, operation(name))
+			<-routineLimiter
+			return nil
+		})
+	}
+
+	_ = group.Wait()
+	close(routineLimiter)
+}
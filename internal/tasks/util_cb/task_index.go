@@ -0,0 +1,363 @@
+package util_cb
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/couchbase/gocb/v2"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/couchbaselabs/sirius/internal/template"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// primaryIndexName is the name reported for a primary index created without a name.
+const primaryIndexName = "#primary"
+
+type IndexTask struct {
+	IdentifierToken string                  `json:"identifierToken" doc:"true"`
+	ClusterConfig   *cb_sdk.ClusterConfig   `json:"clusterConfig" doc:"true"`
+	Bucket          string                  `json:"bucket" doc:"true"`
+	Scope           string                  `json:"scope,omitempty" doc:"true"`
+	Collection      string                  `json:"collection,omitempty" doc:"true"`
+	IndexOptions    *cb_sdk.IndexOptions    `json:"indexOptions,omitempty" doc:"true"`
+	Operation       string                  `json:"operation" doc:"false"`
+	ResultSeed      int64                   `json:"resultSeed" doc:"false"`
+	TaskPending     bool                    `json:"taskPending" doc:"false"`
+	Result          *task_result.TaskResult `json:"-" doc:"false"`
+	req             *tasks.Request          `json:"-" doc:"false"`
+}
+
+func (task *IndexTask) Describe() string {
+	return `Index task manages GSI indexes of a collection without running any query load.
+/create-index creates the indexes defined in indexOptions or the indexes of the template along with a primary
+index, optionally deferred, partitioned and with replicas. /build-index builds the deferred indexes.
+/watch-index waits for the indexes to come online for watchTimeout seconds. /drop-index drops the indexes and
+/list-index lists them. If no indexes are defined, all the indexes of the collection are built, watched, dropped
+or listed. Outcome of every index is reported in the task result.`
+}
+
+func (task *IndexTask) CollectionIdentifier() string {
+	clusterIdentifier, _ := cb_sdk.GetClusterIdentifier(task.ClusterConfig.ConnectionString)
+	return strings.Join([]string{task.IdentifierToken, clusterIdentifier, task.Bucket, task.Scope,
+		task.Collection}, ":")
+}
+
+func (task *IndexTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+// Config configures the task for the index operation set in task.Operation before calling it.
+func (task *IndexTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	switch task.Operation {
+	case tasks.CreateIndexOperation, tasks.BuildIndexOperation, tasks.WatchIndexOperation,
+		tasks.DropIndexOperation, tasks.ListIndexOperation:
+	default:
+		task.TaskPending = false
+		return 0, task_errors.ErrInvalidIndexOperation
+	}
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+
+		if task.Bucket == "" {
+			task.Bucket = cb_sdk.DefaultBucket
+		}
+		if task.Scope == "" {
+			task.Scope = cb_sdk.DefaultScope
+		}
+		if task.Collection == "" {
+			task.Collection = cb_sdk.DefaultCollection
+		}
+
+		if task.IndexOptions == nil {
+			task.IndexOptions = &cb_sdk.IndexOptions{}
+		}
+		if err := cb_sdk.ConfigIndexOptions(task.IndexOptions); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+	} else {
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+	return task.ResultSeed, nil
+}
+
+func (task *IndexTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed, task.Operation)
+	}
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *IndexTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	cluster, err := task.req.GetCluster(task.ClusterConfig)
+	if err != nil {
+		task.Result.ErrorOther = err.Error()
+		return task.TearUp()
+	}
+
+	switch task.Operation {
+	case tasks.CreateIndexOperation:
+		err = createIndexes(task, cluster)
+	case tasks.BuildIndexOperation:
+		err = buildIndexes(task, cluster)
+	case tasks.WatchIndexOperation:
+		err = watchIndexes(task, cluster)
+	case tasks.DropIndexOperation:
+		err = dropIndexes(task, cluster)
+	case tasks.ListIndexOperation:
+		err = listIndexes(task, cluster)
+	}
+	if err != nil {
+		task.Result.ErrorOther = err.Error()
+	}
+
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	return task.TearUp()
+}
+
+func (task *IndexTask) keyspace() string {
+	return fmt.Sprintf("`%s`.`%s`.`%s`", task.Bucket, task.Scope, task.Collection)
+}
+
+func (task *IndexTask) timeout() time.Duration {
+	return time.Duration(task.IndexOptions.Timeout) * time.Second
+}
+
+// recordIndexOutcome saves the outcome of the operation on an index along with the time taken.
+func (task *IndexTask) recordIndexOutcome(indexName string, start time.Time, err error) {
+	outcome := task_result.IndexOutcome{
+		Operation:    task.Operation,
+		DurationInMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		outcome.Error = err.Error()
+	}
+	task.Result.RecordIndexOutcome(indexName, outcome)
+}
+
+// indexDefinitions returns the indexes defined in IndexOptions, or the primary index and the indexes of the
+// template if none are defined.
+func (task *IndexTask) indexDefinitions() ([]cb_sdk.IndexDefinition, error) {
+	if len(task.IndexOptions.Indexes) > 0 {
+		return task.IndexOptions.Indexes, nil
+	}
+
+	templateIndexes, err := template.InitialiseTemplate(task.IndexOptions.Template).GenerateIndexesForSdk()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range templateIndexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	definitions := []cb_sdk.IndexDefinition{{}}
+	for _, name := range names {
+		definitions = append(definitions, cb_sdk.IndexDefinition{Name: name, Fields: templateIndexes[name]})
+	}
+	for i := range definitions {
+		definitions[i].NumReplica = task.IndexOptions.NumReplica
+		definitions[i].NumPartition = task.IndexOptions.NumPartition
+		definitions[i].PartitionBy = task.IndexOptions.PartitionBy
+	}
+	return definitions, nil
+}
+
+// indexNames returns the names of the indexes defined in IndexOptions, or of all the indexes of the
+// collection if none are defined.
+func (task *IndexTask) indexNames(cluster *gocb.Cluster) ([]string, error) {
+	var names []string
+	if len(task.IndexOptions.Indexes) > 0 {
+		for _, d := range task.IndexOptions.Indexes {
+			names = append(names, indexName(d))
+		}
+		return names, nil
+	}
+
+	indexes, err := cluster.QueryIndexes().GetAllIndexes(task.Bucket, &gocb.GetAllQueryIndexesOptions{
+		ScopeName:      task.Scope,
+		CollectionName: task.Collection,
+		Timeout:        task.timeout(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indexes {
+		names = append(names, index.Name)
+	}
+	return names, nil
+}
+
+// indexName returns the name of the index, which is #primary for a primary index defined without a name.
+func indexName(d cb_sdk.IndexDefinition) string {
+	if d.Name == "" {
+		return primaryIndexName
+	}
+	return d.Name
+}
+
+// createIndexStatement returns the N1QL statement for creating an index on a keyspace.
+func createIndexStatement(keyspace string, d cb_sdk.IndexDefinition, deferred bool) string {
+	var statement string
+	if len(d.Fields) == 0 {
+		statement = "CREATE PRIMARY INDEX"
+		if d.Name != "" {
+			statement += fmt.Sprintf(" `%s`", d.Name)
+		}
+		statement += " ON " + keyspace
+	} else {
+		statement = fmt.Sprintf("CREATE INDEX `%s` ON %s(%s)", d.Name, keyspace, strings.Join(d.Fields, ", "))
+	}
+	if d.PartitionBy != "" {
+		statement += " PARTITION BY " + d.PartitionBy
+	}
+	if d.Where != "" && len(d.Fields) > 0 {
+		statement += " WHERE " + d.Where
+	}
+
+	with := map[string]interface{}{"defer_build": deferred}
+	if d.NumReplica > 0 {
+		with["num_replica"] = d.NumReplica
+	}
+	if d.NumPartition > 0 && d.PartitionBy != "" {
+		with["num_partition"] = d.NumPartition
+	}
+	b, _ := json.Marshal(with)
+	return statement + " WITH " + string(b)
+}
+
+// createIndexes creates the indexes one after the other.
+func createIndexes(task *IndexTask, cluster *gocb.Cluster) error {
+	definitions, err := task.indexDefinitions()
+	if err != nil {
+		return err
+	}
+	for _, d := range definitions {
+		if task.req.ContextClosed() {
+			return nil
+		}
+		start := time.Now()
+		_, err := cluster.Query(createIndexStatement(task.keyspace(), d, task.IndexOptions.Deferred),
+			&gocb.QueryOptions{Timeout: task.timeout()})
+		task.recordIndexOutcome(indexName(d), start, err)
+	}
+	return nil
+}
+
+// buildIndexes builds the deferred indexes of the collection.
+func buildIndexes(task *IndexTask, cluster *gocb.Cluster) error {
+	start := time.Now()
+	if len(task.IndexOptions.Indexes) > 0 {
+		var names []string
+		for _, d := range task.IndexOptions.Indexes {
+			names = append(names, fmt.Sprintf("`%s`", indexName(d)))
+		}
+		_, err := cluster.Query(fmt.Sprintf("BUILD INDEX ON %s(%s)", task.keyspace(), strings.Join(names, ", ")),
+			&gocb.QueryOptions{Timeout: task.timeout()})
+		for _, d := range task.IndexOptions.Indexes {
+			task.recordIndexOutcome(indexName(d), start, err)
+		}
+		return nil
+	}
+
+	names, err := cluster.QueryIndexes().BuildDeferredIndexes(task.Bucket, &gocb.BuildDeferredQueryIndexOptions{
+		ScopeName:      task.Scope,
+		CollectionName: task.Collection,
+		Timeout:        task.timeout(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		task.recordIndexOutcome(name, start, nil)
+	}
+	return nil
+}
+
+// watchIndexes waits for the indexes to come online for WatchTimeout seconds.
+func watchIndexes(task *IndexTask, cluster *gocb.Cluster) error {
+	names, err := task.indexNames(cluster)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	err = cluster.QueryIndexes().WatchIndexes(task.Bucket, names,
+		time.Duration(task.IndexOptions.WatchTimeout)*time.Second, &gocb.WatchQueryIndexOptions{
+			WatchPrimary:   task.IndexOptions.WatchPrimary,
+			ScopeName:      task.Scope,
+			CollectionName: task.Collection,
+		})
+	for _, name := range names {
+		task.recordIndexOutcome(name, start, err)
+	}
+	return nil
+}
+
+// dropIndexes drops the indexes one after the other.
+func dropIndexes(task *IndexTask, cluster *gocb.Cluster) error {
+	names, err := task.indexNames(cluster)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if task.req.ContextClosed() {
+			return nil
+		}
+		start := time.Now()
+		_, err := cluster.Query(fmt.Sprintf("DROP INDEX `%s` ON %s", name, task.keyspace()),
+			&gocb.QueryOptions{Timeout: task.timeout()})
+		task.recordIndexOutcome(name, start, err)
+	}
+	return nil
+}
+
+// listIndexes reports the state, keys, condition and partition of the indexes of the collection.
+func listIndexes(task *IndexTask, cluster *gocb.Cluster) error {
+	start := time.Now()
+	indexes, err := cluster.QueryIndexes().GetAllIndexes(task.Bucket, &gocb.GetAllQueryIndexesOptions{
+		ScopeName:      task.Scope,
+		CollectionName: task.Collection,
+		Timeout:        task.timeout(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		task.Result.RecordIndexOutcome(index.Name, task_result.IndexOutcome{
+			Operation:    task.Operation,
+			State:        index.State,
+			IndexKey:     index.IndexKey,
+			Condition:    index.Condition,
+			Partition:    index.Partition,
+			DurationInMs: time.Since(start).Milliseconds(),
+		})
+	}
+	return nil
+}
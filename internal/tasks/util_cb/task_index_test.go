@@ -0,0 +1,27 @@
+package util_cb
+
+import (
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"testing"
+)
+
+func TestCreateIndexStatement(t *testing.T) {
+	keyspace := "`b`.`s`.`c`"
+	for _, tc := range []struct {
+		definition cb_sdk.IndexDefinition
+		deferred   bool
+		expected   string
+	}{
+		{cb_sdk.IndexDefinition{}, false, "CREATE PRIMARY INDEX ON `b`.`s`.`c` WITH {\"defer_build\":false}"},
+		{cb_sdk.IndexDefinition{Name: "p", NumReplica: 1}, true,
+			"CREATE PRIMARY INDEX `p` ON `b`.`s`.`c` WITH {\"defer_build\":true,\"num_replica\":1}"},
+		{cb_sdk.IndexDefinition{Name: "i", Fields: []string{"age", "name"}, Where: "age > 10",
+			PartitionBy: "HASH(meta().id)", NumPartition: 8}, false,
+			"CREATE INDEX `i` ON `b`.`s`.`c`(age, name) PARTITION BY HASH(meta().id) WHERE age > 10 " +
+				"WITH {\"defer_build\":false,\"num_partition\":8}"},
+	} {
+		if statement := createIndexStatement(keyspace, tc.definition, tc.deferred); statement != tc.expected {
+			t.Fatalf("expected %s, got %s", tc.expected, statement)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package util_sirius
+
+import "github.com/couchbaselabs/sirius/internal/tasks"
+
+// ConnectionStatus represents a request structure for retrieving the state of the cluster connections of an
+// identifier.
+type ConnectionStatus struct {
+	IdentifierToken string `json:"identifierToken" doc:"true"`
+	Refresh         bool   `json:"refresh" doc:"true"`
+	TaskPending     bool   `json:"-" doc:"false"`
+}
+
+func (c *ConnectionStatus) Describe() string {
+	return `Connection status returns the state of every cluster and bucket connection of an identifier as per the
+last health check. Connections are checked every 30 seconds and a connection failing 3 consecutive checks is closed
+and reopened by the next task using it. refresh runs a health check before returning the state.`
+}
+
+func (c *ConnectionStatus) tearUp() error {
+	return nil
+}
+
+func (c *ConnectionStatus) Do() error {
+	c.TaskPending = false
+	return nil
+}
+
+func (c *ConnectionStatus) Config(_ *tasks.Request, _ bool) (int64, error) {
+	c.TaskPending = false
+	return 0, nil
+}
@@ -0,0 +1,128 @@
+package util_sirius
+
+import (
+	"fmt"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxCronSearch bounds the search for the next time matching a cron expression.
+const maxCronSearch = 5 * 366 * 24 * time.Hour
+
+// cronSchedule is a parsed cron expression of five fields, minute, hour, day of month, month and day of week,
+// holding the values allowed for every field.
+type cronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek map[int]struct{}
+	anyDayOfMonth, anyDayOfWeek                bool
+}
+
+// parseCron parses a cron expression like */30 * * * * where every field is *, a value, a range a-b, a step */n
+// or a-b/n, or a comma separated list of them. Day of week is 0-6 starting on Sunday, and 7 is also Sunday.
+func parseCron(expression string) (*cronSchedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidCronExpression, expression)
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	values := make([]map[int]struct{}, 5)
+	for i, field := range fields {
+		v, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidCronExpression, expression)
+		}
+		values[i] = v
+	}
+	if _, ok := values[4][7]; ok {
+		values[4][0] = struct{}{}
+	}
+	return &cronSchedule{
+		minute:        values[0],
+		hour:          values[1],
+		dayOfMonth:    values[2],
+		month:         values[3],
+		dayOfWeek:     values[4],
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, min, max int) (map[int]struct{}, error) {
+	values := make(map[int]struct{})
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return nil, task_errors.ErrInvalidCronExpression
+			}
+		}
+
+		start, end := min, max
+		if rangePart != "*" {
+			first, last, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = strconv.Atoi(first); err != nil {
+				return nil, task_errors.ErrInvalidCronExpression
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(last); err != nil {
+					return nil, task_errors.ErrInvalidCronExpression
+				}
+			} else if hasStep {
+				end = max
+			}
+		}
+		if start < min || end > max || start > end {
+			return nil, task_errors.ErrInvalidCronExpression
+		}
+		for v := start; v <= end; v += step {
+			values[v] = struct{}{}
+		}
+	}
+	return values, nil
+}
+
+// next returns the first time after t matching the cron schedule. As in cron, a time matches the days if it
+// matches either the day of month or the day of week when both are restricted.
+func (c *cronSchedule) next(t time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxCronSearch)
+	for t.Before(limit) {
+		if _, ok := c.month[int(t.Month())]; !ok {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if _, ok := c.hour[t.Hour()]; !ok {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if _, ok := c.minute[t.Minute()]; !ok {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func (c *cronSchedule) matchesDay(t time.Time) bool {
+	_, dayOfMonth := c.dayOfMonth[t.Day()]
+	_, dayOfWeek := c.dayOfWeek[int(t.Weekday())]
+	switch {
+	case c.anyDayOfMonth && c.anyDayOfWeek:
+		return true
+	case c.anyDayOfMonth:
+		return dayOfWeek
+	case c.anyDayOfWeek:
+		return dayOfMonth
+	}
+	return dayOfMonth || dayOfWeek
+}
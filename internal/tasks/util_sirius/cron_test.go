@@ -0,0 +1,113 @@
+package util_sirius
+
+import (
+	"errors"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *",
+		"a * * * *", "* * 0 * *", "* * * 13 *", "* * * * 8"} {
+		if _, err := parseCron(expression); !errors.Is(err, task_errors.ErrInvalidCronExpression) {
+			t.Fatalf("expected invalid cron expression for %q, got %v", expression, err)
+		}
+	}
+
+	base := time.Date(2024, time.January, 1, 10, 7, 30, 0, time.UTC) // a Monday
+	for expression, expected := range map[string]time.Time{
+		"* * * * *":    time.Date(2024, time.January, 1, 10, 8, 0, 0, time.UTC),
+		"*/30 * * * *": time.Date(2024, time.January, 1, 10, 30, 0, 0, time.UTC),
+		"0 */12 * * *": time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+		"15 9 * * *":   time.Date(2024, time.January, 2, 9, 15, 0, 0, time.UTC),
+		"0 0 1 * *":    time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"0 0 * * 0":    time.Date(2024, time.January, 7, 0, 0, 0, 0, time.UTC),
+		"0 0 * * 7":    time.Date(2024, time.January, 7, 0, 0, 0, 0, time.UTC),
+		"0 0 15 * 3":   time.Date(2024, time.January, 3, 0, 0, 0, 0, time.UTC),
+		"0 0 29 2 *":   time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+	} {
+		cron, err := parseCron(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next, ok := cron.next(base); !ok || !next.Equal(expected) {
+			t.Fatalf("%q : expected %v, got %v", expression, expected, next)
+		}
+	}
+
+	cron, err := parseCron("5,10-20/5 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := base
+	var minutes []int
+	for i := 0; i < 5; i++ {
+		next, _ = cron.next(next)
+		minutes = append(minutes, next.Minute())
+	}
+	for i, expected := range []int{10, 15, 20, 5, 10} {
+		if minutes[i] != expected {
+			t.Fatalf("expected minutes 10 15 20 5 10, got %v", minutes)
+		}
+	}
+
+	if cron, err = parseCron("0 0 30 2 *"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cron.next(base); ok {
+		t.Fatal("expected no time to match 30th February")
+	}
+}
+
+func TestConfigSchedule(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 10, 7, 30, 0, time.UTC)
+	for _, task := range []*ScheduleTask{
+		{StartAt: "2024-01-01T11:00:00Z", Delay: 10},
+		{Interval: 60, Cron: "* * * * *"},
+		{Until: "2024-01-01T11:00:00Z", Duration: 60},
+		{Interval: -1},
+		{StartAt: "tomorrow"},
+		{Cron: "every minute"},
+	} {
+		if err := task.configSchedule(now); err == nil {
+			t.Fatalf("expected invalid schedule for %+v", task)
+		}
+	}
+
+	task := &ScheduleTask{Delay: 60, Interval: 1800, Duration: 12 * 60 * 60}
+	if err := task.configSchedule(now); err != nil {
+		t.Fatal(err)
+	}
+	if !task.NextRun.Equal(now.Add(time.Minute)) || !task.EndTime.Equal(task.NextRun.Add(12*time.Hour)) {
+		t.Fatalf("unexpected schedule %v to %v", task.NextRun, task.EndTime)
+	}
+	// runs missed while the previous run was running are skipped.
+	if next := task.nextRun(task.NextRun, task.NextRun.Add(time.Hour+time.Second)); !next.Equal(
+		task.NextRun.Add(90 * time.Minute)) {
+		t.Fatalf("unexpected next run %v", next)
+	}
+
+	task = &ScheduleTask{StartAt: "2024-01-01T11:00:00Z", Cron: "0 * * * *", MaxRuns: 2}
+	if err := task.configSchedule(now); err != nil {
+		t.Fatal(err)
+	}
+	if expected := time.Date(2024, time.January, 1, 11, 0, 0, 0, time.UTC); !task.NextRun.Equal(expected) {
+		t.Fatalf("expected first run at %v, got %v", expected, task.NextRun)
+	}
+	if next := task.nextRun(task.NextRun, now); !next.Equal(task.NextRun.Add(time.Hour)) {
+		t.Fatalf("unexpected next run %v", next)
+	}
+	task.Runs = []*ScheduledTaskRun{{}, {}}
+	if !task.scheduleEnded() {
+		t.Fatal("expected schedule to end after maxRuns runs")
+	}
+
+	task = &ScheduleTask{}
+	if err := task.configSchedule(now); err != nil {
+		t.Fatal(err)
+	}
+	if next := task.nextRun(task.NextRun, now); !next.IsZero() {
+		t.Fatalf("expected a single run, got next run %v", next)
+	}
+}
@@ -0,0 +1,475 @@
+package util_sirius
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/couchbaselabs/sirius/internal/tasks/bulk_loading_cb"
+	"github.com/couchbaselabs/sirius/internal/tasks/bulk_query_cb"
+	"github.com/couchbaselabs/sirius/internal/tasks/util_cb"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	StepPending   = "pending"
+	StepRunning   = "running"
+	StepCompleted = "completed"
+	StepSkipped   = "skipped"
+	StepFailed    = "failed"
+)
+
+// newTask returns a new task for an operation which can be run as a step of a plan.
+func newTask(operation string) (tasks.Task, error) {
+	if bulkTask, err := bulk_loading_cb.NewBulkTask(operation); err == nil {
+		return bulkTask, nil
+	}
+	switch operation {
+	case tasks.FanOutOperation:
+		return &bulk_loading_cb.FanOutTask{}, nil
+	case tasks.QueryOperation:
+		return &bulk_query_cb.QueryTask{}, nil
+	case tasks.SearchOperation:
+		return &bulk_query_cb.SearchTask{}, nil
+	case tasks.AnalyticsOperation:
+		return &bulk_query_cb.AnalyticsTask{}, nil
+	case tasks.CreateIndexOperation, tasks.BuildIndexOperation, tasks.WatchIndexOperation, tasks.DropIndexOperation,
+		tasks.ListIndexOperation:
+		return &util_cb.IndexTask{}, nil
+	case tasks.CreateBucketOperation, tasks.DropBucketOperation, tasks.FlushBucketOperation:
+		return &util_cb.BucketTask{}, nil
+	case tasks.CreateScopeOperation, tasks.DropScopeOperation, tasks.CreateCollectionOperation,
+		tasks.DropCollectionOperation:
+		return &util_cb.CollectionTask{}, nil
+	case tasks.BucketWarmUpOperation:
+		return &util_cb.BucketWarmUpTask{}, nil
+	}
+	return nil, fmt.Errorf("%w : %s", task_errors.ErrInvalidPlanOperation, operation)
+}
+
+// buildTask returns the task for an operation from the body of its endpoint. The identifier token of the request
+// is used and the cluster, bucket, scope and collection are used if the body does not have them.
+func buildTask(operation string, body json.RawMessage, identifierToken string, clusterConfig *cb_sdk.ClusterConfig,
+	keyspace map[string]string) (tasks.Task, error) {
+	fields := make(map[string]interface{})
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, err
+		}
+	}
+	fields["identifierToken"] = identifierToken
+	fields["operation"] = operation
+	if _, ok := fields["clusterConfig"]; !ok && clusterConfig != nil {
+		fields["clusterConfig"] = clusterConfig
+	}
+	for key, value := range keyspace {
+		if _, ok := fields[key]; !ok && value != "" {
+			fields[key] = value
+		}
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	task, err := newTask(operation)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// StepCondition decides if a step runs once the steps it depends on are finished. The step runs only if Step, or
+// every step it depends on if Step is not set, has at most MaxFailures failures and at least MinSuccess successes.
+type StepCondition struct {
+	Step        string `json:"step,omitempty" doc:"true"`
+	MaxFailures int64  `json:"maxFailures" doc:"true"`
+	MinSuccess  int64  `json:"minSuccess,omitempty" doc:"true"`
+}
+
+// PlanStep is a task run as a part of a plan once the steps it depends on are finished. Operation is the operation
+// of the task, e.g. insert or query, and Task holds the body of its endpoint. WaitBefore is the time in seconds to
+// wait before running the step.
+type PlanStep struct {
+	Name       string          `json:"name" doc:"true"`
+	Operation  string          `json:"operation" doc:"true"`
+	DependsOn  []string        `json:"dependsOn,omitempty" doc:"true"`
+	WaitBefore int             `json:"waitBefore,omitempty" doc:"true"`
+	Condition  *StepCondition  `json:"condition,omitempty" doc:"true"`
+	Task       json.RawMessage `json:"task" doc:"true"`
+	Status     string          `json:"status" doc:"false"`
+	ResultSeed int64           `json:"resultSeed" doc:"false"`
+	StepTask   tasks.Task      `json:"-" doc:"false"`
+}
+
+type PlanTask struct {
+	IdentifierToken string                  `json:"identifierToken" doc:"true"`
+	ClusterConfig   *cb_sdk.ClusterConfig   `json:"clusterConfig" doc:"true"`
+	Bucket          string                  `json:"bucket,omitempty" doc:"true"`
+	Scope           string                  `json:"scope,omitempty" doc:"true"`
+	Collection      string                  `json:"collection,omitempty" doc:"true"`
+	Steps           []*PlanStep             `json:"steps" doc:"true"`
+	Operation       string                  `json:"operation" doc:"false"`
+	ResultSeed      int64                   `json:"resultSeed" doc:"false"`
+	TaskPending     bool                    `json:"taskPending" doc:"false"`
+	Result          *task_result.TaskResult `json:"-" doc:"false"`
+	req             *tasks.Request          `json:"-" doc:"false"`
+	resume          map[int]struct{}        `json:"-" doc:"false"`
+	stepLock        sync.Mutex              `json:"-" doc:"false"`
+}
+
+// stepEvent is sent by a running step once its task is configured and once it is finished.
+type stepEvent struct {
+	index      int
+	resultSeed int64
+	configured bool
+	err        error
+}
+
+func (task *PlanTask) Describe() string {
+	return `Plan task runs a workload of many steps as a single request. Every step is a task named by name, with
+operation as its operation, e.g. insert, upsert, query or validate, and task as the body of its endpoint. The
+cluster, bucket, scope and collection of the plan are used by the steps which do not have them. A step runs once
+the steps in dependsOn are finished, after waiting for waitBefore seconds, and steps which do not depend on each
+other run in parallel. A step with a condition is skipped, along with the steps depending on it, if the steps it
+refers to have more failures than maxFailures or fewer successes than minSuccess. The result of the plan has the
+status and result seed of every step and is updated as the steps run.`
+}
+
+func (task *PlanTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+// Subtasks returns the tasks of the steps which have started running.
+func (task *PlanTask) Subtasks() []tasks.TaskWithIdentifier {
+	task.stepLock.Lock()
+	defer task.stepLock.Unlock()
+	var subtasks []tasks.TaskWithIdentifier
+	for _, step := range task.Steps {
+		if step.StepTask != nil && step.ResultSeed != 0 {
+			subtasks = append(subtasks, tasks.TaskWithIdentifier{Operation: step.Operation, Task: step.StepTask})
+		}
+	}
+	return subtasks
+}
+
+func (task *PlanTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.req = req
+	task.resume = make(map[int]struct{})
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+		task.Operation = tasks.PlanOperation
+
+		if err := task.validateSteps(); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+		for _, step := range task.Steps {
+			step.Status = StepPending
+			step.ResultSeed = 0
+			step.StepTask = nil
+		}
+
+	} else {
+		for i, step := range task.Steps {
+			if step.Status != StepRunning {
+				continue
+			}
+			if step.StepTask == nil || step.ResultSeed == 0 {
+				step.Status = StepPending
+				continue
+			}
+			if step.StepTask.CheckIfPending() {
+				if _, err := step.StepTask.Config(req, true); err != nil {
+					log.Println(step.Name, err.Error())
+				}
+			}
+			task.resume[i] = struct{}{}
+		}
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+	return task.ResultSeed, nil
+}
+
+func (task *PlanTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed, task.Operation)
+	}
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+// validateSteps checks that the steps have unique names, known operations, valid bodies and conditions and that
+// their dependencies form a DAG.
+func (task *PlanTask) validateSteps() error {
+	if len(task.Steps) == 0 {
+		return task_errors.ErrEmptyPlan
+	}
+	index := make(map[string]int)
+	for i, step := range task.Steps {
+		if step == nil || step.Name == "" {
+			return task_errors.ErrPlanStepNameMissing
+		}
+		if _, ok := index[step.Name]; ok {
+			return fmt.Errorf("%w : %s", task_errors.ErrDuplicatePlanStep, step.Name)
+		}
+		index[step.Name] = i
+		if _, err := task.buildStepTask(step); err != nil {
+			return fmt.Errorf("%s : %w", step.Name, err)
+		}
+	}
+
+	for _, step := range task.Steps {
+		for _, dependency := range step.DependsOn {
+			if _, ok := index[dependency]; !ok {
+				return fmt.Errorf("%w : %s depends on %s", task_errors.ErrUnknownPlanStep, step.Name, dependency)
+			}
+		}
+		if step.Condition != nil && step.Condition.Step != "" && !contains(step.DependsOn, step.Condition.Step) {
+			return fmt.Errorf("%w : %s", task_errors.ErrInvalidPlanCondition, step.Name)
+		}
+	}
+
+	// every step is visited only after its dependencies, which is not possible for the steps in a cycle.
+	visited := make(map[string]struct{})
+	for len(visited) < len(task.Steps) {
+		progressed := false
+		for _, step := range task.Steps {
+			if _, ok := visited[step.Name]; ok {
+				continue
+			}
+			ready := true
+			for _, dependency := range step.DependsOn {
+				if _, ok := visited[dependency]; !ok {
+					ready = false
+				}
+			}
+			if ready {
+				visited[step.Name] = struct{}{}
+				progressed = true
+			}
+		}
+		if !progressed {
+			return task_errors.ErrPlanCycle
+		}
+	}
+	return nil
+}
+
+func (task *PlanTask) buildStepTask(step *PlanStep) (tasks.Task, error) {
+	return buildTask(step.Operation, step.Task, task.IdentifierToken, task.ClusterConfig, map[string]string{
+		"bucket":     task.Bucket,
+		"scope":      task.Scope,
+		"collection": task.Collection,
+	})
+}
+
+// updateStep updates a step holding the lock of the request and of the plan, as the steps are read by Subtasks and
+// saved by SaveRequestIntoFile while the plan runs. Only Do updates the steps, so it reads them without locking.
+func (task *PlanTask) updateStep(step *PlanStep, update func(step *PlanStep)) {
+	task.req.Lock()
+	defer task.req.Unlock()
+	task.stepLock.Lock()
+	defer task.stepLock.Unlock()
+	update(step)
+	task.req.InvalidateTasks()
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (task *PlanTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	index := make(map[string]int)
+	for i, step := range task.Steps {
+		index[step.Name] = i
+		task.recordStep(step, nil)
+	}
+
+	events := make(chan stepEvent, len(task.Steps))
+	running := 0
+	for i := range task.resume {
+		running++
+		go task.runStep(i, events, true)
+	}
+
+	for {
+		if !task.req.ContextClosed() {
+			running += task.startReadySteps(index, events)
+		}
+		if running == 0 {
+			break
+		}
+		_ = task.Result.SaveResultIntoFile()
+
+		event := <-events
+		step := task.Steps[event.index]
+		if event.configured {
+			task.updateStep(step, func(step *PlanStep) { step.ResultSeed = event.resultSeed })
+			task.recordStep(step, nil)
+			continue
+		}
+		running--
+		task.updateStep(step, func(step *PlanStep) {
+			if event.err != nil {
+				step.Status = StepFailed
+			} else {
+				step.Status = StepCompleted
+			}
+		})
+		task.recordStep(step, event.err)
+		_ = task.req.SaveRequestIntoFile()
+	}
+
+	task.Result.Success, task.Result.Failure = 0, 0
+	for _, step := range task.Steps {
+		switch step.Status {
+		case StepCompleted:
+			task.Result.Success++
+		case StepFailed:
+			task.Result.Failure++
+		}
+	}
+
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	return task.TearUp()
+}
+
+// startReadySteps starts the pending steps whose dependencies are finished, or skips them if their condition is
+// not met, and returns the number of steps started.
+func (task *PlanTask) startReadySteps(index map[string]int, events chan stepEvent) int {
+	started := 0
+	for progressed := true; progressed; {
+		progressed = false
+		for i, step := range task.Steps {
+			if step.Status != StepPending {
+				continue
+			}
+			ready, skip := true, false
+			for _, dependency := range step.DependsOn {
+				switch task.Steps[index[dependency]].Status {
+				case StepPending, StepRunning:
+					ready = false
+				case StepSkipped, StepFailed:
+					skip = true
+				}
+			}
+			if !ready {
+				continue
+			}
+			progressed = true
+			if skip || !task.conditionMet(step, index) {
+				task.updateStep(step, func(step *PlanStep) { step.Status = StepSkipped })
+				task.recordStep(step, nil)
+				continue
+			}
+
+			stepTask, err := task.buildStepTask(step)
+			if err != nil {
+				task.updateStep(step, func(step *PlanStep) { step.Status = StepFailed })
+				task.recordStep(step, err)
+				continue
+			}
+			task.updateStep(step, func(step *PlanStep) {
+				step.StepTask = stepTask
+				step.Status = StepRunning
+			})
+			task.recordStep(step, nil)
+			started++
+			go task.runStep(i, events, false)
+		}
+	}
+	return started
+}
+
+// conditionMet returns true if the steps the condition of the step refers to have at most the allowed failures
+// and at least the required successes.
+func (task *PlanTask) conditionMet(step *PlanStep, index map[string]int) bool {
+	if step.Condition == nil {
+		return true
+	}
+	steps := step.DependsOn
+	if step.Condition.Step != "" {
+		steps = []string{step.Condition.Step}
+	}
+	for _, name := range steps {
+		result, ok := task.Result.Steps[name]
+		if !ok || result.Failure > step.Condition.MaxFailures || result.Success < step.Condition.MinSuccess {
+			return false
+		}
+	}
+	return true
+}
+
+// runStep configures and runs the task of a step, or only runs it if the step is resumed after a restart.
+func (task *PlanTask) runStep(i int, events chan stepEvent, resumed bool) {
+	step := task.Steps[i]
+	if !resumed {
+		for wait := 0; wait < step.WaitBefore && !task.req.ContextClosed(); wait++ {
+			time.Sleep(time.Second)
+		}
+		resultSeed, err := step.StepTask.Config(task.req, false)
+		if err != nil {
+			events <- stepEvent{index: i, err: err}
+			return
+		}
+		events <- stepEvent{index: i, resultSeed: resultSeed, configured: true}
+	}
+	if !resumed || step.StepTask.CheckIfPending() {
+		if err := step.StepTask.Do(); err != nil {
+			log.Println(step.Name, err.Error())
+		}
+	}
+	events <- stepEvent{index: i}
+}
+
+// recordStep saves the status of a step along with the result of its task if it is finished.
+func (task *PlanTask) recordStep(step *PlanStep, err error) {
+	stepResult := task_result.PlanStepResult{
+		Operation:  step.Operation,
+		Status:     step.Status,
+		ResultSeed: step.ResultSeed,
+	}
+	if step.Status == StepCompleted && step.ResultSeed != 0 {
+		if result, err := task_result.ReadResultFromFile(fmt.Sprintf("%d", step.ResultSeed), false); err == nil {
+			stepResult.Success = result.Success
+			stepResult.Failure = result.Failure
+			stepResult.ErrorOther = result.ErrorOther
+		} else {
+			stepResult.ErrorOther = err.Error()
+		}
+	}
+	if err != nil {
+		stepResult.ErrorOther = err.Error()
+	}
+	task.Result.RecordPlanStep(step.Name, stepResult)
+}
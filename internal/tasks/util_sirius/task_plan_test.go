@@ -0,0 +1,62 @@
+package util_sirius
+
+import (
+	"errors"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"github.com/couchbaselabs/sirius/internal/tasks/bulk_loading_cb"
+	"testing"
+)
+
+func TestValidateSteps(t *testing.T) {
+	step := func(name, operation string, dependsOn ...string) *PlanStep {
+		return &PlanStep{Name: name, Operation: operation, DependsOn: dependsOn}
+	}
+	for expected, steps := range map[error][]*PlanStep{
+		task_errors.ErrEmptyPlan:            nil,
+		task_errors.ErrDuplicatePlanStep:    {step("a", tasks.InsertOperation), step("a", tasks.UpsertOperation)},
+		task_errors.ErrUnknownPlanStep:      {step("a", tasks.InsertOperation, "b")},
+		task_errors.ErrPlanCycle:            {step("a", tasks.InsertOperation, "b"), step("b", tasks.UpsertOperation, "a")},
+		task_errors.ErrInvalidPlanOperation: {step("a", tasks.SingleInsertOperation)},
+	} {
+		task := &PlanTask{Steps: steps}
+		if err := task.validateSteps(); !errors.Is(err, expected) {
+			t.Fatalf("expected %v, got %v", expected, err)
+		}
+	}
+
+	task := &PlanTask{Steps: []*PlanStep{
+		step("create", tasks.InsertOperation),
+		step("upsert", tasks.UpsertOperation, "create"),
+		step("query", tasks.QueryOperation, "create"),
+		step("validate", tasks.ValidateOperation, "upsert", "query"),
+	}}
+	task.Steps[3].Condition = &StepCondition{Step: "create"}
+	if err := task.validateSteps(); !errors.Is(err, task_errors.ErrInvalidPlanCondition) {
+		t.Fatalf("expected invalid condition, got %v", err)
+	}
+	task.Steps[3].Condition.Step = "upsert"
+	if err := task.validateSteps(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildStepTask(t *testing.T) {
+	task := &PlanTask{
+		IdentifierToken: "plan",
+		ClusterConfig:   &cb_sdk.ClusterConfig{ConnectionString: "couchbase://127.0.0.1"},
+		Bucket:          "b",
+		Scope:           "s",
+	}
+	stepTask, err := task.buildStepTask(&PlanStep{Name: "create", Operation: tasks.InsertOperation,
+		Task: []byte(`{"identifierToken":"other","collection":"c","operationConfig":{"end":10}}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	insertTask := stepTask.(*bulk_loading_cb.InsertTask)
+	if insertTask.IdentifierToken != "plan" || insertTask.Bucket != "b" || insertTask.Scope != "s" ||
+		insertTask.Collection != "c" || insertTask.OperationConfig.End != 10 {
+		t.Fatalf("unexpected task %+v", insertTask)
+	}
+}
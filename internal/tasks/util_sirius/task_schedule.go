@@ -0,0 +1,333 @@
+package util_sirius
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/couchbaselabs/sirius/internal/cb_sdk"
+	"github.com/couchbaselabs/sirius/internal/task_errors"
+	"github.com/couchbaselabs/sirius/internal/task_result"
+	"github.com/couchbaselabs/sirius/internal/tasks"
+	"log"
+	"sync"
+	"time"
+)
+
+// ScheduledTaskRun is a run of the task of a ScheduleTask.
+type ScheduledTaskRun struct {
+	StartTime  time.Time
+	ResultSeed int64
+	Status     string
+	Error      string
+	RunTask    tasks.Task
+}
+
+type ScheduleTask struct {
+	IdentifierToken    string                  `json:"identifierToken" doc:"true"`
+	ClusterConfig      *cb_sdk.ClusterConfig   `json:"clusterConfig" doc:"true"`
+	Bucket             string                  `json:"bucket,omitempty" doc:"true"`
+	Scope              string                  `json:"scope,omitempty" doc:"true"`
+	Collection         string                  `json:"collection,omitempty" doc:"true"`
+	ScheduledOperation string                  `json:"scheduledOperation" doc:"true"`
+	Task               json.RawMessage         `json:"task" doc:"true"`
+	StartAt            string                  `json:"startAt,omitempty" doc:"true"`
+	Delay              int                     `json:"delay,omitempty" doc:"true"`
+	Interval           int                     `json:"interval,omitempty" doc:"true"`
+	Cron               string                  `json:"cron,omitempty" doc:"true"`
+	Until              string                  `json:"until,omitempty" doc:"true"`
+	Duration           int                     `json:"duration,omitempty" doc:"true"`
+	MaxRuns            int                     `json:"maxRuns,omitempty" doc:"true"`
+	NextRun            time.Time               `json:"nextRun" doc:"false"`
+	EndTime            time.Time               `json:"endTime" doc:"false"`
+	Runs               []*ScheduledTaskRun     `json:"-" doc:"false"`
+	Operation          string                  `json:"operation" doc:"false"`
+	ResultSeed         int64                   `json:"resultSeed" doc:"false"`
+	TaskPending        bool                    `json:"taskPending" doc:"false"`
+	Result             *task_result.TaskResult `json:"-" doc:"false"`
+	req                *tasks.Request          `json:"-" doc:"false"`
+	runLock            sync.Mutex              `json:"-" doc:"false"`
+}
+
+func (task *ScheduleTask) Describe() string {
+	return `Schedule task runs a task at a time, after a delay or repeatedly, e.g. validate every 30 minutes
+during a soak. scheduledOperation is the operation of the task, e.g. validate or upsert, and task is the body of its
+endpoint, using the cluster, bucket, scope and collection of the schedule if it does not have them. The first run
+is at startAt (RFC3339) or after delay seconds, and is followed by a run every interval seconds or at every time
+matching the cron expression (minute hour day-of-month month day-of-week), until the time in until (RFC3339), for
+duration seconds from the first run or for maxRuns runs. A schedule without interval or cron runs once. Runs do not
+overlap and a run delayed by the previous one starts as soon as it finishes. Schedules are saved with the
+request and continue after Sirius restarts. The result has the status and result seed of every run.`
+}
+
+func (task *ScheduleTask) CheckIfPending() bool {
+	return task.TaskPending
+}
+
+// Subtasks returns the tasks of the runs of the schedule.
+func (task *ScheduleTask) Subtasks() []tasks.TaskWithIdentifier {
+	task.runLock.Lock()
+	defer task.runLock.Unlock()
+	var subtasks []tasks.TaskWithIdentifier
+	for _, run := range task.Runs {
+		if run.RunTask != nil && run.ResultSeed != 0 {
+			subtasks = append(subtasks, tasks.TaskWithIdentifier{Operation: task.ScheduledOperation, Task: run.RunTask})
+		}
+	}
+	return subtasks
+}
+
+func (task *ScheduleTask) Config(req *tasks.Request, reRun bool) (int64, error) {
+	task.TaskPending = true
+	task.req = req
+
+	if task.req == nil {
+		task.TaskPending = false
+		return 0, task_errors.ErrRequestIsNil
+	}
+
+	task.req.ReconnectionManager()
+	if _, err := task.req.GetCluster(task.ClusterConfig); err != nil {
+		task.TaskPending = false
+		return 0, err
+	}
+
+	if !reRun {
+		task.ResultSeed = int64(time.Now().UnixNano())
+		task.Operation = tasks.ScheduleOperation
+		task.Runs = nil
+
+		if _, err := task.buildRunTask(); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+		if err := task.configSchedule(time.Now()); err != nil {
+			task.TaskPending = false
+			return 0, err
+		}
+
+	} else {
+		if run := task.lastRun(); run != nil && run.Status == StepRunning {
+			if run.RunTask == nil || run.ResultSeed == 0 {
+				run.Status = StepFailed
+				run.Error = task_errors.ErrScheduledRunInterrupted.Error()
+			} else if run.RunTask.CheckIfPending() {
+				if _, err := run.RunTask.Config(req, true); err != nil {
+					log.Println(task.ScheduledOperation, err.Error())
+				}
+			}
+		}
+		log.Println("retrying :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	}
+	return task.ResultSeed, nil
+}
+
+// configSchedule validates the schedule and sets the time of the first run and the time the schedule ends.
+func (task *ScheduleTask) configSchedule(now time.Time) error {
+	if task.Delay < 0 || task.Interval < 0 || task.Duration < 0 || task.MaxRuns < 0 {
+		return fmt.Errorf("%w : delay, interval, duration and maxRuns cannot be negative",
+			task_errors.ErrInvalidSchedule)
+	}
+	if task.StartAt != "" && task.Delay > 0 {
+		return fmt.Errorf("%w : only one of startAt and delay can be set", task_errors.ErrInvalidSchedule)
+	}
+	if task.Interval > 0 && task.Cron != "" {
+		return fmt.Errorf("%w : only one of interval and cron can be set", task_errors.ErrInvalidSchedule)
+	}
+	if task.Until != "" && task.Duration > 0 {
+		return fmt.Errorf("%w : only one of until and duration can be set", task_errors.ErrInvalidSchedule)
+	}
+
+	start := now.Add(time.Duration(task.Delay) * time.Second)
+	if task.StartAt != "" {
+		var err error
+		if start, err = time.Parse(time.RFC3339, task.StartAt); err != nil {
+			return fmt.Errorf("%w : %s", task_errors.ErrInvalidSchedule, err.Error())
+		}
+	}
+	if task.Cron != "" {
+		cron, err := parseCron(task.Cron)
+		if err != nil {
+			return err
+		}
+		// the first run is at the first time matching the cron expression from start.
+		var ok bool
+		if start, ok = cron.next(start.Add(-time.Nanosecond)); !ok {
+			return fmt.Errorf("%w : no time matches %s", task_errors.ErrInvalidSchedule, task.Cron)
+		}
+	}
+	task.NextRun = start
+
+	task.EndTime = time.Time{}
+	if task.Until != "" {
+		var err error
+		if task.EndTime, err = time.Parse(time.RFC3339, task.Until); err != nil {
+			return fmt.Errorf("%w : %s", task_errors.ErrInvalidSchedule, err.Error())
+		}
+	} else if task.Duration > 0 {
+		task.EndTime = start.Add(time.Duration(task.Duration) * time.Second)
+	}
+	return nil
+}
+
+// nextRun returns the time of the run following a run scheduled at previous, which is not before now. It returns
+// zero time if the task does not recur.
+func (task *ScheduleTask) nextRun(previous time.Time, now time.Time) time.Time {
+	if task.Interval > 0 {
+		next := previous.Add(time.Duration(task.Interval) * time.Second)
+		for next.Before(now) {
+			next = next.Add(time.Duration(task.Interval) * time.Second)
+		}
+		return next
+	}
+	if task.Cron != "" {
+		if cron, err := parseCron(task.Cron); err == nil {
+			if previous.After(now) {
+				now = previous
+			}
+			if next, ok := cron.next(now); ok {
+				return next
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// scheduleEnded returns true if no more runs are due as per the end time and the maximum number of runs.
+func (task *ScheduleTask) scheduleEnded() bool {
+	if task.NextRun.IsZero() {
+		return true
+	}
+	if !task.EndTime.IsZero() && task.NextRun.After(task.EndTime) {
+		return true
+	}
+	return task.MaxRuns > 0 && len(task.Runs) >= task.MaxRuns
+}
+
+func (task *ScheduleTask) lastRun() *ScheduledTaskRun {
+	if len(task.Runs) == 0 {
+		return nil
+	}
+	return task.Runs[len(task.Runs)-1]
+}
+
+// updateRuns updates the runs holding the lock of the request and of the schedule, as the runs are read by Subtasks
+// and saved by SaveRequestIntoFile while the schedule runs. Only Do updates the runs, so it reads them without
+// locking.
+func (task *ScheduleTask) updateRuns(update func()) {
+	task.req.Lock()
+	defer task.req.Unlock()
+	task.runLock.Lock()
+	defer task.runLock.Unlock()
+	update()
+	task.req.InvalidateTasks()
+}
+
+func (task *ScheduleTask) buildRunTask() (tasks.Task, error) {
+	return buildTask(task.ScheduledOperation, task.Task, task.IdentifierToken, task.ClusterConfig, map[string]string{
+		"bucket":     task.Bucket,
+		"scope":      task.Scope,
+		"collection": task.Collection,
+	})
+}
+
+func (task *ScheduleTask) TearUp() error {
+	task.Result.StopStoringResult()
+	if err := task.Result.SaveResultIntoFile(); err != nil {
+		log.Println("not able to save Result into ", task.ResultSeed, task.Operation)
+	}
+	task.TaskPending = false
+	return task.req.SaveRequestIntoFile()
+}
+
+func (task *ScheduleTask) Do() error {
+
+	task.Result = task_result.ConfigTaskResult(task.Operation, task.ResultSeed)
+
+	// finish the run which was in progress before Sirius restarted.
+	if run := task.lastRun(); run != nil && run.Status == StepRunning && run.RunTask != nil {
+		task.finishRun(len(task.Runs)-1, run.RunTask.CheckIfPending())
+	}
+
+	for !task.req.ContextClosed() && !task.scheduleEnded() {
+		for time.Now().Before(task.NextRun) && !task.req.ContextClosed() {
+			wait := time.Until(task.NextRun)
+			if wait > time.Second {
+				wait = time.Second
+			}
+			time.Sleep(wait)
+		}
+		if task.req.ContextClosed() {
+			break
+		}
+
+		// the run is added once its task is configured, so that a saved running run always has its task.
+		run := &ScheduledTaskRun{StartTime: time.Now(), Status: StepRunning}
+		runTask, err := task.buildRunTask()
+		if err == nil {
+			run.ResultSeed, err = runTask.Config(task.req, false)
+		}
+		if err == nil {
+			run.RunTask = runTask
+		} else {
+			run.Status = StepFailed
+			run.Error = err.Error()
+		}
+		task.updateRuns(func() { task.Runs = append(task.Runs, run) })
+		task.NextRun = task.nextRun(task.NextRun, time.Now())
+
+		if err != nil {
+			task.recordRun(len(task.Runs) - 1)
+		} else {
+			task.recordRun(len(task.Runs) - 1)
+			_ = task.req.SaveRequestIntoFile()
+			task.finishRun(len(task.Runs)-1, true)
+		}
+		_ = task.Result.SaveResultIntoFile()
+		_ = task.req.SaveRequestIntoFile()
+	}
+
+	task.Result.Success, task.Result.Failure = 0, 0
+	for _, run := range task.Runs {
+		switch run.Status {
+		case StepCompleted:
+			task.Result.Success++
+		case StepFailed:
+			task.Result.Failure++
+		}
+	}
+
+	log.Println("completed :- ", task.Operation, task.IdentifierToken, task.ResultSeed)
+	return task.TearUp()
+}
+
+// finishRun runs the task of a run, if required, and records it as completed.
+func (task *ScheduleTask) finishRun(index int, runTask bool) {
+	run := task.Runs[index]
+	if runTask {
+		if err := run.RunTask.Do(); err != nil {
+			log.Println(task.ScheduledOperation, err.Error())
+		}
+	}
+	task.updateRuns(func() { run.Status = StepCompleted })
+	task.recordRun(index)
+}
+
+// recordRun saves the status of a run along with the result of its task if it is completed.
+func (task *ScheduleTask) recordRun(index int) {
+	run := task.Runs[index]
+	scheduledRun := task_result.ScheduledRun{
+		StartTime:  run.StartTime.Format(time.RFC3339),
+		Status:     run.Status,
+		ResultSeed: run.ResultSeed,
+		ErrorOther: run.Error,
+	}
+	if run.Status == StepCompleted {
+		if result, err := task_result.ReadResultFromFile(fmt.Sprintf("%d", run.ResultSeed), false); err == nil {
+			scheduledRun.Success = result.Success
+			scheduledRun.Failure = result.Failure
+			scheduledRun.ErrorOther = result.ErrorOther
+		} else {
+			scheduledRun.ErrorOther = err.Error()
+		}
+	}
+	task.Result.RecordScheduledRun(index, scheduledRun)
+}
@@ -9,8 +9,47 @@ const (
 	MutatedPath             string  = "mutated"
 	MutatedPathDefaultValue float64 = 0
 	MutateFieldIncrement    float64 = 1
+	SearchFieldText         string  = "text"
+	SearchFieldNumber       string  = "number"
+	SearchFieldBoolean      string  = "boolean"
+	SearchFieldGeoPoint     string  = "geopoint"
 )
 
+// SearchField is a document field indexed by a full text search index. Path is the dotted path of the
+// field in the document and Type is one of text, number, boolean or geopoint.
+type SearchField struct {
+	Path string
+	Type string
+}
+
+// QueryCheck is a query whose rows can be computed from the documents expected to exist in a collection.
+// NewAggregate returns an empty RowAggregate to which the expected documents are added.
+type QueryCheck struct {
+	Query        string
+	NewAggregate func() RowAggregate
+}
+
+// RowAggregate computes the rows expected for a QueryCheck from documents added one at a time, so that the
+// documents need not be held in memory. Add receives a document as a map, decoded from its JSON.
+type RowAggregate interface {
+	Add(document map[string]interface{})
+	Rows() []map[string]interface{}
+}
+
+// rowAggregate is a RowAggregate built from its functions.
+type rowAggregate struct {
+	add  func(document map[string]interface{})
+	rows func() []map[string]interface{}
+}
+
+func (a *rowAggregate) Add(document map[string]interface{}) {
+	a.add(document)
+}
+
+func (a *rowAggregate) Rows() []map[string]interface{} {
+	return a.rows()
+}
+
 type Template interface {
 	GenerateDocument(fake *faker.Faker, documentSize int) (interface{}, error)
 	UpdateDocument(fieldsToChange []string, lastUpdatedDocument interface{}, documentSize int,
@@ -19,6 +58,9 @@ type Template interface {
 	GenerateIndexes(bucketName string, scopeName string, collectionName string) ([]string, error)
 	GenerateQueries(bucketName string, scopeName string, collectionName string) ([]string, error)
 	GenerateIndexesForSdk() (map[string][]string, error)
+	GenerateSearchFields() ([]SearchField, error)
+	GenerateAnalyticsQueries(datasetName string, joinDatasetName string) ([]string, error)
+	GenerateQueryChecks(bucketName string, scopeName string, collectionName string) ([]QueryCheck, error)
 	GenerateSubPathAndValue(fake *faker.Faker, subDocSize int) map[string]any
 }
 
@@ -29,6 +71,8 @@ func InitialiseTemplate(template string) Template {
 		return &Person{}
 	case "hotel":
 		return &Hotel{}
+	case "hotel_geo":
+		return &Hotel{withGeo: true}
 	case "small":
 		return &SmallTemplate{}
 	default:
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jaswdr/faker"
+	"hash/fnv"
+	"math/rand"
 	"reflect"
 	"time"
 )
@@ -22,6 +24,11 @@ type Review struct {
 	Rating Rating `json:"rating,omitempty"`
 }
 
+type Geo struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
 type Hotel struct {
 	Country       string   `json:"country,omitempty"`
 	Address       string   `json:"address,omitempty"`
@@ -37,8 +44,19 @@ type Hotel struct {
 	Name          string   `json:"name,omitempty"`
 	PublicLikes   []string `json:"public_likes,omitempty"`
 	Email         string   `json:"email,omitempty"`
+	Geo           *Geo     `json:"geo,omitempty"`
 	Mutated       float64  `json:"mutated"`
 	Padding       string   `json:"padding"`
+	withGeo       bool
+}
+
+// buildGeo derives a geo point from seed with its own random source, so that adding it to a hotel does not consume
+// the faker stream the rest of the document is generated from.
+func buildGeo(seed string) *Geo {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(seed))
+	r := rand.New(rand.NewSource(int64(h.Sum64())))
+	return &Geo{Lat: r.Float64()*180 - 90, Lon: r.Float64()*360 - 180}
 }
 
 func buildReview(fake *faker.Faker, length int) []Review {
@@ -85,6 +103,9 @@ func (h *Hotel) GenerateDocument(fake *faker.Faker, documentSize int) (interface
 		Email:         fake.Internet().CompanyEmail(),
 		Mutated:       MutatedPathDefaultValue,
 	}
+	if h.withGeo {
+		hotel.Geo = buildGeo(hotel.Name + hotel.Address + hotel.Phone)
+	}
 
 	hotelDocument, err := json.Marshal(*hotel)
 	if err != nil {
@@ -150,6 +171,9 @@ func (h *Hotel) UpdateDocument(fieldsToChange []string, lastUpdatedDocument inte
 	if _, ok := checkFields["email"]; ok || len(checkFields) == 0 {
 		hotel.Email = fake.Internet().CompanyEmail()
 	}
+	if _, ok := checkFields["geo"]; h.withGeo && (ok || len(checkFields) == 0) {
+		hotel.Geo = buildGeo(fmt.Sprintf("%s%v", hotel.Name, hotel.Geo))
+	}
 	hotel.Padding = ""
 	hotelDocument, err := json.Marshal(*hotel)
 	if err != nil {
@@ -188,6 +212,29 @@ func (h *Hotel) GenerateIndexesForSdk() (map[string][]string, error) {
 	return map[string][]string{}, errors.New("not implemented")
 }
 
+func (h *Hotel) GenerateAnalyticsQueries(datasetName string, joinDatasetName string) ([]string, error) {
+	return []string{}, errors.New("not implemented")
+}
+
+func (h *Hotel) GenerateQueryChecks(bucketName string, scopeName string, collectionName string) ([]QueryCheck,
+	error) {
+	return []QueryCheck{}, errors.New("not implemented")
+}
+
+func (h *Hotel) GenerateSearchFields() ([]SearchField, error) {
+	return []SearchField{
+		{Path: "name", Type: SearchFieldText},
+		{Path: "country", Type: SearchFieldText},
+		{Path: "city", Type: SearchFieldText},
+		{Path: "address", Type: SearchFieldText},
+		{Path: "price", Type: SearchFieldNumber},
+		{Path: "avg_rating", Type: SearchFieldNumber},
+		{Path: "free_parking", Type: SearchFieldBoolean},
+		{Path: "free_breakfast", Type: SearchFieldBoolean},
+		{Path: "geo", Type: SearchFieldGeoPoint},
+	}, nil
+}
+
 func (h *Hotel) GenerateSubPathAndValue(fake *faker.Faker, subDocSize int) map[string]any {
 	return map[string]interface{}{
 		"subDocData": fake.RandomStringWithLength(subDocSize),
@@ -76,3 +76,26 @@ func TestGenerateHotel(t *testing.T) {
 	}
 
 }
+
+func TestGenerateHotelGeo(t *testing.T) {
+	fake1 := faker.NewWithSeed(rand.NewSource(1678693916037126000))
+	fake2 := faker.NewWithSeed(rand.NewSource(1678693916037126000))
+	hotel, _ := InitialiseTemplate("hotel").GenerateDocument(&fake1, 0)
+	hotelGeo, _ := InitialiseTemplate("hotel_geo").GenerateDocument(&fake2, 0)
+
+	if hotel.(*Hotel).Geo != nil {
+		t.Fatal("expected the hotel template to not generate a geo point")
+	}
+	geo := hotelGeo.(*Hotel).Geo
+	if geo == nil || geo.Lat < -90 || geo.Lat > 90 || geo.Lon < -180 || geo.Lon > 180 {
+		t.Fatalf("unexpected geo point %+v", geo)
+	}
+	// the geo point does not consume the faker stream shared with the rest of the document.
+	if fake1.Int64() != fake2.Int64() {
+		t.Fatal("expected the geo point to not consume the faker stream")
+	}
+	hotelGeo.(*Hotel).Geo = nil
+	if ok, _ := InitialiseTemplate("hotel").Compare(hotel, hotelGeo); !ok {
+		t.Fatal("expected the hotel_geo template to only add a geo point")
+	}
+}
@@ -1,6 +1,9 @@
 package template
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 func (p *Person) GenerateQueries(bucketName string, scopeName string, collectionName string) ([]string, error) {
 	return []string{
@@ -49,3 +52,165 @@ func (p *Person) GenerateIndexesForSdk() (map[string][]string, error) {
 		"ix_hair_type_color":      []string{"attributes.hair.colour", "attributes.hair.type"},
 	}, nil
 }
+
+func (p *Person) GenerateSearchFields() ([]SearchField, error) {
+	return []SearchField{
+		{Path: "firstName", Type: SearchFieldText},
+		{Path: "email", Type: SearchFieldText},
+		{Path: "gender", Type: SearchFieldText},
+		{Path: "maritalStatus", Type: SearchFieldText},
+		{Path: "hobbies", Type: SearchFieldText},
+		{Path: "address.city", Type: SearchFieldText},
+		{Path: "address.state", Type: SearchFieldText},
+		{Path: "attributes.hair.type", Type: SearchFieldText},
+		{Path: "age", Type: SearchFieldNumber},
+		{Path: "attributes.weight", Type: SearchFieldNumber},
+		{Path: "attributes.height", Type: SearchFieldNumber},
+	}, nil
+}
+
+func (p *Person) GenerateAnalyticsQueries(datasetName string, joinDatasetName string) ([]string, error) {
+	queries := []string{
+		fmt.Sprintf("SELECT VALUE COUNT(*) FROM %s;", datasetName),
+		fmt.Sprintf("SELECT p.gender, COUNT(*) AS cnt, AVG(p.age) AS avgAge FROM %s p GROUP BY p.gender;", datasetName),
+		fmt.Sprintf("SELECT p.address.state AS state, COUNT(*) AS cnt FROM %s p GROUP BY p.address.state ORDER BY cnt DESC LIMIT 10;",
+			datasetName),
+		fmt.Sprintf("SELECT p.maritalStatus, MIN(p.age) AS minAge, MAX(p.age) AS maxAge FROM %s p GROUP BY p.maritalStatus;",
+			datasetName),
+		fmt.Sprintf("SELECT hobby, COUNT(*) AS cnt FROM %s p UNNEST p.hobbies AS hobby GROUP BY hobby ORDER BY cnt DESC;",
+			datasetName),
+		fmt.Sprintf("SELECT p.attributes.hair.type AS hairType, AVG(p.attributes.weight) AS avgWeight, AVG(p.attributes.height) AS avgHeight FROM %s p GROUP BY p.attributes.hair.type;",
+			datasetName),
+	}
+	if joinDatasetName != "" {
+		queries = append(queries,
+			fmt.Sprintf("SELECT a.state, a.cnt AS cnt1, b.cnt AS cnt2 FROM (SELECT p.address.state AS state, COUNT(*) AS cnt FROM %s p GROUP BY p.address.state) a JOIN (SELECT p.address.state AS state, COUNT(*) AS cnt FROM %s p GROUP BY p.address.state) b ON a.state = b.state;",
+				datasetName, joinDatasetName),
+			fmt.Sprintf("SELECT a.firstName, a.age, b.age AS joinedAge FROM %s a JOIN %s b ON a.firstName = b.firstName AND a.gender = b.gender LIMIT 100;",
+				datasetName, joinDatasetName),
+		)
+	}
+	return queries, nil
+}
+
+// isPersonDocument returns true if the document is generated using the person template.
+func isPersonDocument(document map[string]interface{}) bool {
+	_, ok := document["firstName"]
+	return ok
+}
+
+// genderGroups counts person documents by gender the way GROUP BY gender does in N1QL. Documents without a gender
+// are counted in the MISSING group and documents with a null gender in the NULL group.
+type genderGroups struct {
+	missing float64
+	null    float64
+	genders map[string]float64
+}
+
+func (g *genderGroups) Add(document map[string]interface{}) {
+	if !isPersonDocument(document) {
+		return
+	}
+	gender, ok := document["gender"]
+	switch {
+	case !ok:
+		g.missing++
+	case gender == nil:
+		g.null++
+	default:
+		g.genders[fmt.Sprint(gender)]++
+	}
+}
+
+// Rows returns a row for every group, ordered as ORDER BY gender orders them, MISSING first and NULL next. The
+// row of the MISSING group has no gender, as N1QL omits a MISSING value from the projection.
+func (g *genderGroups) Rows() []map[string]interface{} {
+	rows := []map[string]interface{}{}
+	if g.missing > 0 {
+		rows = append(rows, map[string]interface{}{"cnt": g.missing})
+	}
+	if g.null > 0 {
+		rows = append(rows, map[string]interface{}{"gender": nil, "cnt": g.null})
+	}
+	var genders []string
+	for gender := range g.genders {
+		genders = append(genders, gender)
+	}
+	sort.Strings(genders)
+	for _, gender := range genders {
+		rows = append(rows, map[string]interface{}{"gender": gender, "cnt": g.genders[gender]})
+	}
+	return rows
+}
+
+func (p *Person) GenerateQueryChecks(bucketName string, scopeName string, collectionName string) ([]QueryCheck,
+	error) {
+	keyspace := fmt.Sprintf("`%s`.`%s`.`%s`", bucketName, scopeName, collectionName)
+	return []QueryCheck{
+		{
+			Query: fmt.Sprintf("SELECT COUNT(*) AS cnt FROM %s WHERE firstName IS NOT MISSING;", keyspace),
+			NewAggregate: func() RowAggregate {
+				count := 0
+				return &rowAggregate{
+					add: func(document map[string]interface{}) {
+						if isPersonDocument(document) {
+							count++
+						}
+					},
+					rows: func() []map[string]interface{} {
+						return []map[string]interface{}{{"cnt": float64(count)}}
+					},
+				}
+			},
+		},
+		{
+			Query: fmt.Sprintf("SELECT COUNT(*) AS cnt FROM %s WHERE firstName IS NOT MISSING AND age BETWEEN 30 AND 50;",
+				keyspace),
+			NewAggregate: func() RowAggregate {
+				count := 0
+				return &rowAggregate{
+					add: func(document map[string]interface{}) {
+						if age, ok := document["age"].(float64); ok && isPersonDocument(document) && age >= 30 &&
+							age <= 50 {
+							count++
+						}
+					},
+					rows: func() []map[string]interface{} {
+						return []map[string]interface{}{{"cnt": float64(count)}}
+					},
+				}
+			},
+		},
+		{
+			Query: fmt.Sprintf("SELECT gender, COUNT(*) AS cnt FROM %s WHERE firstName IS NOT MISSING GROUP BY gender ORDER BY gender;",
+				keyspace),
+			NewAggregate: func() RowAggregate {
+				return &genderGroups{genders: make(map[string]float64)}
+			},
+		},
+		{
+			Query: fmt.Sprintf("SELECT MIN(age) AS minAge, MAX(age) AS maxAge FROM %s WHERE firstName IS NOT MISSING AND age IS NOT MISSING;",
+				keyspace),
+			NewAggregate: func() RowAggregate {
+				row := map[string]interface{}{"minAge": nil, "maxAge": nil}
+				return &rowAggregate{
+					add: func(document map[string]interface{}) {
+						age, ok := document["age"].(float64)
+						if !ok || !isPersonDocument(document) {
+							return
+						}
+						if minAge, ok := row["minAge"].(float64); !ok || age < minAge {
+							row["minAge"] = age
+						}
+						if maxAge, ok := row["maxAge"].(float64); !ok || age > maxAge {
+							row["maxAge"] = age
+						}
+					},
+					rows: func() []map[string]interface{} {
+						return []map[string]interface{}{row}
+					},
+				}
+			},
+		},
+	}, nil
+}
@@ -1,6 +1,7 @@
 package template
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/jaswdr/faker"
 	"log"
@@ -79,3 +80,35 @@ func TestGeneratePerson(t *testing.T) {
 	}
 
 }
+
+func TestPersonQueryChecks(t *testing.T) {
+	documents := []map[string]interface{}{
+		{"firstName": "a", "age": float64(35), "gender": "male"},
+		{"firstName": "b", "age": float64(60), "gender": "female"},
+		{"firstName": "c", "gender": "male"},
+		{"firstName": "d", "age": float64(40)},
+		{"firstName": "e", "gender": nil},
+		{"f": "not a person"},
+	}
+	checks, err := InitialiseTemplate("person").GenerateQueryChecks("b", "s", "c")
+	if err != nil || len(checks) != 4 {
+		t.Fatalf("expected 4 query checks, got %d %v", len(checks), err)
+	}
+
+	expected := []string{
+		`[{"cnt":5}]`,
+		`[{"cnt":2}]`,
+		`[{"cnt":1},{"cnt":1,"gender":null},{"cnt":1,"gender":"female"},{"cnt":2,"gender":"male"}]`,
+		`[{"maxAge":60,"minAge":35}]`,
+	}
+	for i, check := range checks {
+		aggregate := check.NewAggregate()
+		for _, document := range documents {
+			aggregate.Add(document)
+		}
+		b, _ := json.Marshal(aggregate.Rows())
+		if string(b) != expected[i] {
+			t.Fatalf("%s : expected %s, got %s", check.Query, expected[i], b)
+		}
+	}
+}
@@ -53,6 +53,23 @@ func (s *SmallTemplate) GenerateIndexesForSdk() (map[string][]string, error) {
 	return map[string][]string{}, nil
 }
 
+func (s *SmallTemplate) GenerateSearchFields() ([]SearchField, error) {
+	return []SearchField{
+		{Path: "d", Type: SearchFieldText},
+	}, nil
+}
+
+func (s *SmallTemplate) GenerateAnalyticsQueries(datasetName string, joinDatasetName string) ([]string, error) {
+	return []string{
+		fmt.Sprintf("SELECT VALUE COUNT(*) FROM %s;", datasetName),
+	}, nil
+}
+
+func (s *SmallTemplate) GenerateQueryChecks(bucketName string, scopeName string, collectionName string) ([]QueryCheck,
+	error) {
+	return []QueryCheck{}, nil
+}
+
 func (s *SmallTemplate) GenerateSubPathAndValue(fake *faker.Faker, subDocSize int) map[string]any {
 
 	return map[string]interface{}{